@@ -0,0 +1,42 @@
+// ll2mock serves LL2-shaped responses from JSON fixtures, point LL2_URL_PREFIX at it
+// to run syncs without access to thespacedevs
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vamosdalian/launchdate-backend/internal/ll2mock"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "listen address")
+	fixtures := flag.String("fixtures", "cmd/ll2mock/fixtures", "directory of <endpoint>.json fixtures")
+	version := flag.String("version", "2.3.0", "LL2 API version served")
+	var faults ll2mock.Faults
+	flag.IntVar(&faults.ThrottleEvery, "throttle-every", 0, "answer every nth request with a 429")
+	flag.IntVar(&faults.RetryAfter, "retry-after", 1, "Retry-After of injected 429s in seconds")
+	flag.IntVar(&faults.ErrorEvery, "error-every", 0, "answer every nth request with -error-status")
+	flag.IntVar(&faults.ErrorStatus, "error-status", http.StatusServiceUnavailable, "status of injected errors")
+	flag.DurationVar(&faults.Delay, "delay", 0, "delay before every response")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetOutput(os.Stdout)
+
+	results, err := ll2mock.LoadFixtures(*fixtures)
+	if err != nil {
+		logger.Fatalf("failed to load fixtures: %v", err)
+	}
+	for endpoint, r := range results {
+		logger.Infof("serving %d %s at /%s/%s/", len(r), endpoint, *version, endpoint)
+	}
+
+	logger.Infof("ll2mock listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, ll2mock.New(*version, results, faults)); err != nil {
+		logger.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/vamosdalian/launchdate-backend/internal/api"
 	"github.com/vamosdalian/launchdate-backend/internal/config"
 	"github.com/vamosdalian/launchdate-backend/internal/db"
+	"github.com/vamosdalian/launchdate-backend/internal/service"
 )
 
 func main() {
@@ -33,7 +34,20 @@ func main() {
 	defer cleandb()
 	logger.Infof("create mongodb database: %s", cfg.MongodbDatabase)
 
-	handler := api.NewHandler(logger, cfg, db)
+	ll2Service := service.NewLL2Service(cfg, db)
+	if err := ll2Service.ValidateSettings(); err != nil {
+		logger.Fatalf("invalid LL2 request settings: %v", err)
+	}
+	if err := ll2Service.EnsureIndexes(context.Background()); err != nil {
+		logger.Fatalf("failed to create indexes: %v", err)
+	}
+	scheduler, err := service.NewScheduler(ll2Service, cfg.LL2Schedules)
+	if err != nil {
+		logger.Fatalf("failed to create sync scheduler: %v", err)
+	}
+	scheduler.Start()
+
+	handler := api.NewHandler(logger, ll2Service)
 	router := api.SetupRouter(handler)
 
 	// Create HTTP server
@@ -68,5 +82,14 @@ func main() {
 		logger.Fatalf("server forced to shutdown: %v", err)
 	}
 
+	if err := scheduler.Stop(ctx); err != nil {
+		logger.Errorf("sync scheduler forced to stop: %v", err)
+	}
+
+	// cancel in-flight syncs and give them the rest of the grace period to return
+	if err := ll2Service.Jobs().Shutdown(ctx); err != nil {
+		logger.Errorf("sync jobs forced to stop: %v", err)
+	}
+
 	logger.Info("server stopped")
 }
@@ -3,8 +3,6 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
-	"github.com/vamosdalian/launchdate-backend/internal/config"
-	"github.com/vamosdalian/launchdate-backend/internal/db"
 	"github.com/vamosdalian/launchdate-backend/internal/service"
 )
 
@@ -15,8 +13,7 @@ type Handler struct {
 }
 
 // NewHandler creates a new handler
-func NewHandler(logger *logrus.Logger, cfg *config.Config, db *db.MongoDB) *Handler {
-	ll2server := service.NewLL2Service(cfg, db)
+func NewHandler(logger *logrus.Logger, ll2server *service.LL2Service) *Handler {
 	return &Handler{
 		logger:    logger,
 		ll2Server: ll2server,
@@ -1,126 +1,253 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vamosdalian/launchdate-backend/internal/service"
 )
 
-func (h *Handler) GetLL2Launches(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	launches, err := h.ll2Server.GetLaunchesFromDB(limit, offset)
-	if err != nil {
-		h.Error(c, "failed to get launches: "+err.Error())
-		return
+// maxListLimit is the largest page served by list endpoints
+const maxListLimit = 100
+
+// pageParams reads ?limit=, defaulting to defaultLimit, and ?offset= from the query
+func pageParams(c *gin.Context, defaultLimit int) (limit, offset int, err error) {
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit < 1 || limit > maxListLimit {
+		return 0, 0, fmt.Errorf("invalid limit %q, must be between 1 and %d", c.Query("limit"), maxListLimit)
 	}
-	h.Json(c, launches)
+	offset, err = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("invalid offset %q, must not be negative", c.Query("offset"))
+	}
+	return limit, offset, nil
 }
 
-func (h *Handler) StartLL2LaunchUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdateLaunches(true)
+// listOptions reads the page from the query, every other query parameter is passed on as a filter
+// and rejected by the service unless the listed resource declares it
+func listOptions(c *gin.Context) (service.ListOptions, error) {
+	limit, offset, err := pageParams(c, 10)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
+		return service.ListOptions{}, err
 	}
-	c.JSON(200, gin.H{"status": "LL2 launch update started"})
+	includeRemoved, err := strconv.ParseBool(c.DefaultQuery("include_removed", "false"))
+	if err != nil {
+		return service.ListOptions{}, fmt.Errorf("invalid include_removed %q", c.Query("include_removed"))
+	}
+	filters := map[string]string{}
+	for param, values := range c.Request.URL.Query() {
+		switch param {
+		case "limit", "offset", "include_removed":
+		default:
+			filters[param] = values[0]
+		}
+	}
+	return service.ListOptions{
+		Limit:          limit,
+		Offset:         offset,
+		IncludeRemoved: includeRemoved,
+		Filters:        filters,
+	}, nil
 }
 
-func (h *Handler) StartLL2AngecyUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdateAngecy(true)
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
+// GetLL2Resource returns a handler listing the named resource from DB
+func (h *Handler) GetLL2Resource(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, err := listOptions(c)
+		if err != nil {
+			h.Error(c, err.Error())
+			return
+		}
+		items, err := h.ll2Server.List(c.Request.Context(), name, opts)
+		if err != nil {
+			h.Error(c, "failed to get "+name+": "+err.Error())
+			return
+		}
+		h.Json(c, items)
 	}
-	c.JSON(200, gin.H{"status": "LL2 angecy update started"})
 }
 
-func (h *Handler) GetLL2Angecy(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	angecies, err := h.ll2Server.GetAngecyFromDB(limit, offset)
-	if err != nil {
-		h.Error(c, "failed to get angecies: "+err.Error())
-		return
+// GetLL2Launches returns a handler listing the launches linked to a record of the named resource,
+// they can be filtered like the launch list
+func (h *Handler) GetLL2Launches(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		opts, err := listOptions(c)
+		if err != nil {
+			h.Error(c, err.Error())
+			return
+		}
+		items, err := h.ll2Server.ListLaunches(c.Request.Context(), name, c.Param("id"), opts)
+		if err != nil {
+			h.Error(c, "failed to get "+name+" launches: "+err.Error())
+			return
+		}
+		h.Json(c, items)
 	}
-	h.Json(c, angecies)
 }
 
-func (h *Handler) GetLL2LauncherFamilies(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	families, err := h.ll2Server.GetLauncherFamiliesFromDB(limit, offset)
+// GetLL2UpcomingLaunches lists the launches that have not launched yet, soonest first
+func (h *Handler) GetLL2UpcomingLaunches(c *gin.Context) {
+	opts, err := listOptions(c)
+	if err != nil {
+		h.Error(c, err.Error())
+		return
+	}
+	items, err := h.ll2Server.ListUpcomingLaunches(c.Request.Context(), opts)
 	if err != nil {
-		h.Error(c, "failed to get launcher families: "+err.Error())
+		h.Error(c, "failed to get upcoming launches: "+err.Error())
 		return
 	}
-	h.Json(c, families)
+	h.Json(c, items)
 }
 
-func (h *Handler) GetLL2Launchers(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	launchers, err := h.ll2Server.GetLaunchersFromDB(limit, offset)
+// GetLL2PreviousLaunches lists the launches that have launched, most recent first
+func (h *Handler) GetLL2PreviousLaunches(c *gin.Context) {
+	opts, err := listOptions(c)
 	if err != nil {
-		h.Error(c, "failed to get launchers: "+err.Error())
+		h.Error(c, err.Error())
 		return
 	}
-	h.Json(c, launchers)
+	items, err := h.ll2Server.ListPreviousLaunches(c.Request.Context(), opts)
+	if err != nil {
+		h.Error(c, "failed to get previous launches: "+err.Error())
+		return
+	}
+	h.Json(c, items)
 }
 
-func (h *Handler) StartLL2LauncherUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdateLaunchersAsync(true)
+// GetLL2Detail returns a handler returning a single stored record of the named resource by id or slug,
+// or a 404 if none matches
+func (h *Handler) GetLL2Detail(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		item, err := h.ll2Server.Get(c.Request.Context(), name, c.Param("id"))
+		if errors.Is(err, service.ErrNotFound) {
+			h.NotFound(c, name+" "+c.Param("id")+" not found")
+			return
+		}
+		if err != nil {
+			h.Error(c, "failed to get "+name+": "+err.Error())
+			return
+		}
+		h.Json(c, item)
+	}
+}
+
+// GetLL2History returns a handler listing the recorded changes of a record of the named resource
+func (h *Handler) GetLL2History(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, offset, err := pageParams(c, 100)
+		if err != nil {
+			h.Error(c, err.Error())
+			return
+		}
+		entries, err := h.ll2Server.History(c.Request.Context(), name, c.Param("id"), service.ListOptions{
+			Limit:  limit,
+			Offset: offset,
+		})
+		if err != nil {
+			h.Error(c, "failed to get "+name+" history: "+err.Error())
+			return
+		}
+		h.Json(c, entries)
+	}
+}
+
+// StartLL2Update returns a handler starting a sync of the named resource.
+// Resources that sync incrementally can be fully resynced with ?full=true.
+func (h *Handler) StartLL2Update(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		full, _ := strconv.ParseBool(c.DefaultQuery("full", "false"))
+		job, err := h.ll2Server.Update(c.Request.Context(), name, true, service.SyncOptions{Full: full})
+		if err != nil {
+			h.Error(c, "start error:"+err.Error())
+			return
+		}
+		h.Json(c, job)
+	}
+}
+
+// GetLL2DockedVehicles lists the vehicles currently docked at each space station
+func (h *Handler) GetLL2DockedVehicles(c *gin.Context) {
+	stations, err := h.ll2Server.DockedVehicles(c.Request.Context())
 	if err != nil {
-		h.Error(c, "start error:"+err.Error())
+		h.Error(c, "failed to get docked vehicles: "+err.Error())
 		return
 	}
-	h.Success(c, "ok")
+	h.Json(c, stations)
 }
 
-func (h *Handler) StartLL2LauncherFamilyUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdateLauncherFamiliesAsync(true)
+// GetLL2Changes lists the field change log, filtered by ?entity=, ?id= and
+// an RFC 3339 time range ?since= (inclusive) and ?until= (exclusive)
+func (h *Handler) GetLL2Changes(c *gin.Context) {
+	limit, offset, err := pageParams(c, 100)
 	if err != nil {
-		h.Error(c, "start error:"+err.Error())
+		h.Error(c, err.Error())
 		return
 	}
-	h.Success(c, "ok")
+	q := service.ChangeQuery{
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("id"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if since := c.Query("since"); since != "" {
+		if q.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			h.Error(c, "invalid since: "+err.Error())
+			return
+		}
+	}
+	if until := c.Query("until"); until != "" {
+		if q.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			h.Error(c, "invalid until: "+err.Error())
+			return
+		}
+	}
+	changes, err := h.ll2Server.Changes(c.Request.Context(), q)
+	if err != nil {
+		h.Error(c, "failed to get changes: "+err.Error())
+		return
+	}
+	h.Json(c, changes)
 }
 
-func (h *Handler) StartLL2LocationUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdateLocationsAsync(true)
+func (h *Handler) GetLL2Jobs(c *gin.Context) {
+	h.Json(c, h.ll2Server.Jobs().List())
+}
+
+func (h *Handler) GetLL2Job(c *gin.Context) {
+	job, err := h.ll2Server.Jobs().Get(c.Param("id"))
 	if err != nil {
-		h.Error(c, "start error:"+err.Error())
+		h.Error(c, "failed to get job: "+err.Error())
 		return
 	}
-	h.Success(c, "ok")
+	h.Json(c, job)
 }
 
-func (h *Handler) GetLL2Locations(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	locations, err := h.ll2Server.GetLocationsFromDB(limit, offset)
+func (h *Handler) CancelLL2Job(c *gin.Context) {
+	err := h.ll2Server.Jobs().Cancel(c.Param("id"))
 	if err != nil {
-		h.Error(c, "failed to get locations: "+err.Error())
+		h.Error(c, "failed to cancel job: "+err.Error())
 		return
 	}
-	h.Json(c, locations)
+	h.Success(c, "ok")
 }
 
-func (h *Handler) GetLL2Pads(c *gin.Context) {
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	pads, err := h.ll2Server.GetPadsFromDB(limit, offset)
+func (h *Handler) GetLL2Checkpoints(c *gin.Context) {
+	checkpoints, err := h.ll2Server.GetCheckpoints(c.Request.Context())
 	if err != nil {
-		h.Error(c, "failed to get pads: "+err.Error())
+		h.Error(c, "failed to get checkpoints: "+err.Error())
 		return
 	}
-	h.Json(c, pads)
+	h.Json(c, checkpoints)
 }
 
-func (h *Handler) StartLL2PadUpdate(c *gin.Context) {
-	err := h.ll2Server.UpdatePadsAsync(true)
+func (h *Handler) ResetLL2Checkpoint(c *gin.Context) {
+	err := h.ll2Server.ClearCheckpoint(c.Request.Context(), c.Param("resource"))
 	if err != nil {
-		h.Error(c, "start error:"+err.Error())
+		h.Error(c, "failed to reset checkpoint: "+err.Error())
 		return
 	}
 	h.Success(c, "ok")
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/service"
+)
+
+func TestListRejectsInvalidQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := SetupRouter(NewHandler(logrus.New(), service.NewLL2Service(&config.Config{}, nil)))
+
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{"/api/v1/ll2/launches?provider=121", "failed to get launches: LL2 resource launches can not be filtered by provider"},
+		{"/api/v1/ll2/launches/upcoming?net_from=2025-01-01T00:00:00Z", "failed to get upcoming launches: LL2 resource launches can not be filtered by net_from"},
+		{"/api/v1/ll2/launches?net_after=tomorrow", "failed to get launches: invalid net_after: parsing time \"tomorrow\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"tomorrow\" as \"2006\""},
+		{"/api/v1/ll2/launches?limit=abc", "invalid limit \"abc\", must be between 1 and 100"},
+		{"/api/v1/ll2/launches?limit=0", "invalid limit \"0\", must be between 1 and 100"},
+		{"/api/v1/ll2/pads?limit=1000", "invalid limit \"1000\", must be between 1 and 100"},
+		{"/api/v1/ll2/pads?offset=-1", "invalid offset \"-1\", must not be negative"},
+		{"/api/v1/ll2/pads?include_removed=maybe", "invalid include_removed \"maybe\""},
+		{"/api/v1/ll2/changes?limit=abc", "invalid limit \"abc\", must be between 1 and 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.query, nil))
+
+			var resp Response
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, CodeFailed, resp.Code)
+			assert.Equal(t, tt.message, resp.Message)
+		})
+	}
+}
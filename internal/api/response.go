@@ -28,6 +28,13 @@ func (h *Handler) Error(c *gin.Context, msg string) {
 	})
 }
 
+func (h *Handler) NotFound(c *gin.Context, msg string) {
+	c.JSON(404, Response{
+		Code:    CodeFailed,
+		Message: msg,
+	})
+}
+
 func (h *Handler) Success(c *gin.Context, msg string) {
 	c.JSON(200, Response{
 		Code:    CodeSuccess,
@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/vamosdalian/launchdate-backend/internal/middleware"
+	"github.com/vamosdalian/launchdate-backend/internal/service"
 )
 
 // SetupRouter sets up the API routes
@@ -17,18 +18,30 @@ func SetupRouter(handler *Handler) *gin.Engine {
 		apiV1.GET("/health", handler.Health)
 		ll2 := apiV1.Group("/ll2")
 		{
-			ll2.GET("/launches", handler.GetLL2Launches)
-			ll2.POST("/launches/update", handler.StartLL2LaunchUpdate)
-			ll2.GET("/angecies", handler.GetLL2Angecy)
-			ll2.POST("/angecies/update", handler.StartLL2AngecyUpdate)
-			ll2.GET("/launcher-families", handler.GetLL2LauncherFamilies)
-			ll2.GET("/launchers", handler.GetLL2Launchers)
-			ll2.POST("/launchers/update", handler.StartLL2LauncherUpdate)
-			ll2.POST("/launcher-families/update", handler.StartLL2LauncherFamilyUpdate)
-			ll2.GET("/locations", handler.GetLL2Locations)
-			ll2.POST("/locations/update", handler.StartLL2LocationUpdate)
-			ll2.GET("/pads", handler.GetLL2Pads)
-			ll2.POST("/pads/update", handler.StartLL2PadUpdate)
+			for _, r := range service.Resources() {
+				ll2.GET("/"+r.Name, handler.GetLL2Resource(r.Name))
+				ll2.POST("/"+r.Name+"/update", handler.StartLL2Update(r.Name))
+				ll2.GET("/"+r.Name+"/:id", handler.GetLL2Detail(r.Name))
+				if r.LaunchesField != "" {
+					ll2.GET("/"+r.Name+"/:id/launches", handler.GetLL2Launches(r.Name))
+				}
+				if len(r.HistoryFields) > 0 {
+					ll2.GET("/"+r.Name+"/:id/history", handler.GetLL2History(r.Name))
+				}
+			}
+			ll2.GET("/launches/upcoming", handler.GetLL2UpcomingLaunches)
+			ll2.GET("/launches/previous", handler.GetLL2PreviousLaunches)
+			ll2.GET("/space-stations/docked", handler.GetLL2DockedVehicles)
+			// the misspelled agency routes are kept for existing clients
+			ll2.GET("/angecies", handler.GetLL2Resource("agencies"))
+			ll2.POST("/angecies/update", handler.StartLL2Update("agencies"))
+
+			ll2.GET("/changes", handler.GetLL2Changes)
+			ll2.GET("/jobs", handler.GetLL2Jobs)
+			ll2.GET("/jobs/:id", handler.GetLL2Job)
+			ll2.POST("/jobs/:id/cancel", handler.CancelLL2Job)
+			ll2.GET("/checkpoints", handler.GetLL2Checkpoints)
+			ll2.DELETE("/checkpoints/:resource", handler.ResetLL2Checkpoint)
 		}
 	}
 
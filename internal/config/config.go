@@ -2,17 +2,44 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/sethvargo/go-envconfig"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server             ServerConfig
-	MongodbURL         string `env:"MONGODB_URL"`
-	MongodbDatabase    string `env:"MONGODB_DATABASE"`
-	LL2URLPrefix       string `env:"LL2_URL_PREFIX"`
-	LL2RequestInterval int    `env:"LL2_REQUEST_INTERVAL, default=5"` // in seconds
+	Server          ServerConfig
+	MongodbURL      string `env:"MONGODB_URL"`
+	MongodbDatabase string `env:"MONGODB_DATABASE"`
+	LL2URLPrefix    string `env:"LL2_URL_PREFIX"`
+	// LL2APIToken authenticates LL2 requests, raising the request budget to the tier of the token
+	LL2APIToken string `env:"LL2_API_TOKEN"`
+	// LL2RequestInterval is in seconds, if unset it follows the request budget of LL2APIToken,
+	// or is 5 for anonymous requests
+	LL2RequestInterval int `env:"LL2_REQUEST_INTERVAL"`
+	// LL2APIVersion, LL2Mode and LL2PageSize are how resources are requested from LL2,
+	// LL2APIVersions, LL2Modes and LL2PageSizes override them per resource, e.g. "launches=normal;agencies=list"
+	LL2APIVersion  string            `env:"LL2_API_VERSION, default=2.3.0"`
+	LL2Mode        string            `env:"LL2_MODE, default=detailed"` // list, normal or detailed, resources without it use detailed
+	LL2PageSize    int               `env:"LL2_PAGE_SIZE, default=10"`
+	LL2APIVersions map[string]string `env:"LL2_API_VERSIONS, delimiter=;, separator=="`
+	LL2Modes       map[string]string `env:"LL2_MODES, delimiter=;, separator=="`
+	LL2PageSizes   map[string]int    `env:"LL2_PAGE_SIZES, delimiter=;, separator=="`
+	// LL2MaxRetries is how often a transient LL2 failure (429, 5xx, network error) is retried
+	LL2MaxRetries     int           `env:"LL2_MAX_RETRIES, default=5"`
+	LL2RetryBaseDelay time.Duration `env:"LL2_RETRY_BASE_DELAY, default=2s"`
+	LL2RetryMaxDelay  time.Duration `env:"LL2_RETRY_MAX_DELAY, default=2m"`
+	// LL2AdaptiveRate paces syncs by the request budget LL2 reports at /api-throttle/
+	// instead of the fixed LL2RequestInterval
+	LL2AdaptiveRate bool `env:"LL2_ADAPTIVE_RATE, default=true"`
+	// LL2Transport records LL2 responses to LL2FixtureDir ("record"),
+	// or serves them from there without network access ("replay")
+	LL2Transport  string `env:"LL2_TRANSPORT"`
+	LL2FixtureDir string `env:"LL2_FIXTURE_DIR, default=testdata/ll2"`
+	// LL2Schedules maps a resource to the cron expression its sync runs on,
+	// e.g. "launches=*/15 * * * *;agencies=@daily;pads=@weekly"
+	LL2Schedules map[string]string `env:"LL2_SCHEDULES, delimiter=;, separator=="`
 }
 
 // ServerConfig holds server configuration
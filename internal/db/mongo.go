@@ -36,6 +36,6 @@ func NewMongoDB(uri, database string) (*MongoDB, func(), error) {
 		}, nil
 }
 
-func (db *MongoDB) Collection(name string) *mongo.Collection {
-	return db.Client.Database(db.Database).Collection(name)
+func (db *MongoDB) Collection(name string, opts ...*options.CollectionOptions) *mongo.Collection {
+	return db.Client.Database(db.Database).Collection(name, opts...)
 }
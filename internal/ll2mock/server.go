@@ -0,0 +1,286 @@
+// Package ll2mock serves LL2-shaped API responses from JSON fixtures,
+// for integration tests and environments without access to thespacedevs
+package ll2mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Faults are failures injected into responses
+type Faults struct {
+	// ThrottleEvery answers every nth request with a 429, 0 disables it
+	ThrottleEvery int
+	// RetryAfter is sent with injected 429s, in seconds
+	RetryAfter int
+	// ErrorEvery answers every nth request with ErrorStatus, 0 disables it
+	ErrorEvery  int
+	ErrorStatus int
+	// Delay is waited before every response
+	Delay time.Duration
+}
+
+// Server serves the results of every fixture as a paginated LL2 endpoint
+type Server struct {
+	version  string
+	faults   Faults
+	mu       sync.Mutex
+	requests int
+	// results by endpoint, e.g. "launches" or "spacecraft/flights"
+	results map[string][]map[string]any
+}
+
+// New returns a server for the LL2 API version serving fixtures
+func New(version string, fixtures map[string][]map[string]any, faults Faults) *Server {
+	if faults.ErrorStatus == 0 {
+		faults.ErrorStatus = http.StatusServiceUnavailable
+	}
+	return &Server{version: version, faults: faults, results: fixtures}
+}
+
+// LoadFixtures reads every .json file below dir as the results of the endpoint named by its path,
+// e.g. dir/spacecraft/flights.json serves /<version>/spacecraft/flights/.
+// A file holds either a list of results or an LL2 page with results.
+func LoadFixtures(dir string) (map[string][]map[string]any, error) {
+	fixtures := map[string][]map[string]any{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		results, err := parseFixture(data)
+		if err != nil {
+			return fmt.Errorf("fixture %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fixtures[filepath.ToSlash(strings.TrimSuffix(rel, ".json"))] = results
+		return nil
+	})
+	return fixtures, err
+}
+
+func parseFixture(data []byte) ([]map[string]any, error) {
+	var results []map[string]any
+	if err := json.Unmarshal(data, &results); err == nil {
+		return results, nil
+	}
+	var page struct {
+		Results []map[string]any `json:"results"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	n := s.count()
+	if s.faults.Delay > 0 {
+		select {
+		case <-time.After(s.faults.Delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+	if s.faults.ThrottleEvery > 0 && n%s.faults.ThrottleEvery == 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(s.faults.RetryAfter))
+		writeJSON(rw, http.StatusTooManyRequests, map[string]any{"detail": "Request was throttled."})
+		return
+	}
+	if s.faults.ErrorEvery > 0 && n%s.faults.ErrorEvery == 0 {
+		writeJSON(rw, s.faults.ErrorStatus, map[string]any{"detail": http.StatusText(s.faults.ErrorStatus)})
+		return
+	}
+
+	endpoint, ok := strings.CutPrefix(strings.Trim(req.URL.Path, "/"), s.version+"/")
+	if !ok {
+		writeJSON(rw, http.StatusNotFound, map[string]any{"detail": "Not found."})
+		return
+	}
+	if endpoint == "api-throttle" {
+		writeJSON(rw, http.StatusOK, map[string]any{
+			"your_request_limit":   1000000,
+			"limit_frequency_secs": 3600,
+			"current_use":          n,
+			"next_use_secs":        0,
+			"ident":                req.RemoteAddr,
+		})
+		return
+	}
+	results, ok := s.results[endpoint]
+	if !ok {
+		writeJSON(rw, http.StatusNotFound, map[string]any{"detail": "Not found."})
+		return
+	}
+	page, err := paginate(req, filter(results, req), s.baseURL(req, endpoint))
+	if err != nil {
+		writeJSON(rw, http.StatusBadRequest, map[string]any{"detail": err.Error()})
+		return
+	}
+	writeJSON(rw, http.StatusOK, page)
+}
+
+// count returns the number of the current request, starting at 1
+func (s *Server) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	return s.requests
+}
+
+func (s *Server) baseURL(req *http.Request, endpoint string) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/%s/%s/", scheme, req.Host, s.version, endpoint)
+}
+
+// filter applies the LL2 field lookups <field>__gte, __gt, __lte and __lt and ordering=[-]<field>
+// on top level fields, other query parameters are ignored
+func filter(results []map[string]any, req *http.Request) []map[string]any {
+	query := req.URL.Query()
+	filtered := []map[string]any{}
+	for _, result := range results {
+		if matches(result, query) {
+			filtered = append(filtered, result)
+		}
+	}
+	if ordering := query.Get("ordering"); ordering != "" {
+		field, desc := strings.CutPrefix(ordering, "-")
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if desc {
+				return less(filtered[j][field], filtered[i][field])
+			}
+			return less(filtered[i][field], filtered[j][field])
+		})
+	}
+	return filtered
+}
+
+func matches(result map[string]any, query map[string][]string) bool {
+	for key, values := range query {
+		field, lookup, ok := strings.Cut(key, "__")
+		if !ok || len(values) == 0 {
+			continue
+		}
+		value, bound := result[field], values[0]
+		switch lookup {
+		case "gte":
+			if less(value, bound) {
+				return false
+			}
+		case "gt":
+			if !less(bound, value) {
+				return false
+			}
+		case "lte":
+			if less(bound, value) {
+				return false
+			}
+		case "lt":
+			if !less(value, bound) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// less compares numbers numerically and everything else, e.g. RFC 3339 times, as strings
+func less(a, b any) bool {
+	af, aErr := strconv.ParseFloat(fmt.Sprint(a), 64)
+	bf, bErr := strconv.ParseFloat(fmt.Sprint(b), 64)
+	if aErr == nil && bErr == nil {
+		return af < bf
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// paginate returns the LL2 page of results selected by limit and offset
+func paginate(req *http.Request, results []map[string]any, base string) (map[string]any, error) {
+	limit, err := intParam(req, "limit", defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > maxLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	offset, err := intParam(req, "offset", 0)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	end := min(offset+limit, len(results))
+	page := []map[string]any{}
+	if offset < len(results) {
+		page = results[offset:end]
+	}
+
+	var next, previous any
+	if end < len(results) {
+		next = pageURL(req, base, limit, end)
+	}
+	if offset > 0 {
+		previous = pageURL(req, base, limit, max(offset-limit, 0))
+	}
+	return map[string]any{
+		"count":    len(results),
+		"next":     next,
+		"previous": previous,
+		"results":  page,
+	}, nil
+}
+
+// pageURL returns the URL of the page at offset, keeping the other query parameters of req
+func pageURL(req *http.Request, base string, limit, offset int) string {
+	query := req.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return base + "?" + query.Encode()
+}
+
+func intParam(req *http.Request, name string, def int) (int, error) {
+	value := req.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+func writeJSON(rw http.ResponseWriter, status int, payload any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	if err := json.NewEncoder(rw).Encode(payload); err != nil {
+		logrus.Warnf("failed to write response: %s", err)
+	}
+}
@@ -0,0 +1,119 @@
+package ll2mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fixtures() map[string][]map[string]any {
+	return map[string][]map[string]any{
+		"agencies": {
+			{"id": 1, "name": "a"},
+			{"id": 2, "name": "b"},
+			{"id": 3, "name": "c"},
+		},
+		"launches": {
+			{"id": "x", "last_updated": "2024-10-30T13:39:57Z"},
+			{"id": "y", "last_updated": "2024-09-01T00:00:00Z"},
+			{"id": "z", "last_updated": "2024-11-02T08:00:00Z"},
+		},
+	}
+}
+
+func get(t *testing.T, server *httptest.Server, path string) (*http.Response, map[string]any) {
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body map[string]any
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func TestPagination(t *testing.T) {
+	server := httptest.NewServer(New("2.3.0", fixtures(), Faults{}))
+	defer server.Close()
+
+	resp, page := get(t, server, "/2.3.0/agencies/?limit=2&offset=0&mode=detailed")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 3, page["count"])
+	assert.Len(t, page["results"], 2)
+	assert.Equal(t, server.URL+"/2.3.0/agencies/?limit=2&mode=detailed&offset=2", page["next"])
+	assert.Nil(t, page["previous"])
+
+	_, page = get(t, server, "/2.3.0/agencies?limit=2&offset=2")
+	assert.Len(t, page["results"], 1)
+	assert.Nil(t, page["next"])
+	assert.Equal(t, server.URL+"/2.3.0/agencies/?limit=2&offset=0", page["previous"])
+
+	_, page = get(t, server, "/2.3.0/agencies/?offset=5")
+	assert.EqualValues(t, 3, page["count"])
+	assert.Empty(t, page["results"])
+
+	resp, _ = get(t, server, "/2.3.0/agencies/?limit=500")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = get(t, server, "/2.3.0/rockets/")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestIncrementalFilter(t *testing.T) {
+	server := httptest.NewServer(New("2.3.0", fixtures(), Faults{}))
+	defer server.Close()
+
+	_, page := get(t, server, "/2.3.0/launches/?last_updated__gte=2024-10-30T13:39:57Z&ordering=-last_updated")
+	assert.EqualValues(t, 2, page["count"])
+	results := page["results"].([]any)
+	assert.Equal(t, "z", results[0].(map[string]any)["id"])
+	assert.Equal(t, "x", results[1].(map[string]any)["id"])
+}
+
+func TestInjectedFaults(t *testing.T) {
+	server := httptest.NewServer(New("2.3.0", fixtures(), Faults{ThrottleEvery: 2, RetryAfter: 7, ErrorEvery: 3}))
+	defer server.Close()
+
+	statuses := []int{}
+	for range 4 {
+		resp, _ := get(t, server, "/2.3.0/agencies/")
+		statuses = append(statuses, resp.StatusCode)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			assert.Equal(t, "7", resp.Header.Get("Retry-After"))
+		}
+	}
+	assert.Equal(t, []int{200, 429, 503, 429}, statuses)
+}
+
+func TestDelay(t *testing.T) {
+	server := httptest.NewServer(New("2.3.0", fixtures(), Faults{Delay: 50 * time.Millisecond}))
+	defer server.Close()
+
+	start := time.Now()
+	resp, _ := get(t, server, "/2.3.0/agencies/")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestLoadFixtures(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "spacecraft"), 0o755)
+	os.WriteFile(filepath.Join(dir, "pads.json"), []byte(`[{"id": 87}]`), 0o644)
+	os.WriteFile(filepath.Join(dir, "spacecraft", "flights.json"), []byte(`{"count": 1, "results": [{"id": 1250}]}`), 0o644)
+
+	results, err := LoadFixtures(dir)
+	assert.NoError(t, err)
+	assert.Len(t, results["pads"], 1)
+	assert.Len(t, results["spacecraft/flights"], 1)
+
+	// the bundled fixtures load
+	results, err = LoadFixtures(filepath.Join("..", "..", "cmd", "ll2mock", "fixtures"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, results["launches"])
+}
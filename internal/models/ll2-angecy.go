@@ -38,6 +38,7 @@ type LL2AgencyNormal struct {
 
 type LL2AgencyDetailed struct {
 	LL2AgencyNormal               `bson:",inline"`
+	LL2Tombstone                  `bson:",inline"`
 	TotalLaunchCount              int                  `json:"total_launch_count" bson:"total_launch_count"`
 	ConsecutiveSuccessfulLaunches int                  `json:"consecutive_successful_launches" bson:"consecutive_successful_launches"`
 	SuccessfulLaunches            int                  `json:"successful_launches" bson:"successful_launches"`
@@ -56,6 +57,8 @@ type LL2AgencyDetailed struct {
 	InfoURL                       string               `json:"info_url" bson:"info_url"`
 	WikiURL                       string               `json:"wiki_url" bson:"wiki_url"`
 	SocialMediaLinks              []LL2SocialMediaLink `json:"social_media_links" bson:"social_media_links"`
-	// TODO launcher_list
-	// TODO spacecraft_list
+	// LauncherList and SpacecraftList link the configurations the agency builds,
+	// by id into the launchers and spacecraft-configurations collections
+	LauncherList   []LL2LauncherConfigList   `json:"launcher_list" bson:"launcher_list"`
+	SpacecraftList []LL2SpacecraftConfigMini `json:"spacecraft_list" bson:"spacecraft_list"`
 }
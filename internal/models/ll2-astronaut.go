@@ -0,0 +1,44 @@
+package models
+
+type LL2AstronautStatus struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2AstronautType struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2AstronautNormal struct {
+	ID              int                  `json:"id" bson:"id"`
+	URL             string               `json:"url" bson:"url"`
+	ResponseMode    string               `json:"response_mode" bson:"response_mode"`
+	Name            string               `json:"name" bson:"name"`
+	Status          LL2AstronautStatus   `json:"status" bson:"status"`
+	Agency          LL2AgencyMini        `json:"agency" bson:"agency"`
+	Image           LL2Image             `json:"image" bson:"image"`
+	Type            LL2AstronautType     `json:"type" bson:"type"`
+	InSpace         bool                 `json:"in_space" bson:"in_space"`
+	TimeInSpace     string               `json:"time_in_space" bson:"time_in_space"`
+	EvaTime         string               `json:"eva_time" bson:"eva_time"`
+	Age             int                  `json:"age" bson:"age"`
+	DateOfBirth     string               `json:"date_of_birth" bson:"date_of_birth"`
+	DateOfDeath     string               `json:"date_of_death" bson:"date_of_death"`
+	Nationality     []LL2Country         `json:"nationality" bson:"nationality"`
+	Bio             string               `json:"bio" bson:"bio"`
+	Wiki            string               `json:"wiki" bson:"wiki"`
+	LastFlight      string               `json:"last_flight" bson:"last_flight"`
+	FirstFlight     string               `json:"first_flight" bson:"first_flight"`
+	SocialMediaLink []LL2SocialMediaLink `json:"social_media_links" bson:"social_media_links"`
+}
+
+type LL2AstronautDetailed struct {
+	LL2AstronautNormal `bson:",inline"`
+	LL2Tombstone       `bson:",inline"`
+	FlightsCount       int `json:"flights_count" bson:"flights_count"`
+	LandingsCount      int `json:"landings_count" bson:"landings_count"`
+	SpacewalksCount    int `json:"spacewalks_count" bson:"spacewalks_count"`
+	// flights are stored without their nested details, the launches collection has those
+	Flights []LL2LaunchBasic `json:"flights" bson:"flights"`
+}
@@ -0,0 +1,33 @@
+package models
+
+type LL2EventType struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2EventDetailed struct {
+	ID            int                   `json:"id" bson:"id"`
+	URL           string                `json:"url" bson:"url"`
+	Name          string                `json:"name" bson:"name"`
+	Slug          string                `json:"slug" bson:"slug"`
+	ResponseMode  string                `json:"response_mode" bson:"response_mode"`
+	Type          LL2EventType          `json:"type" bson:"type"`
+	Description   string                `json:"description" bson:"description"`
+	WebcastLive   bool                  `json:"webcast_live" bson:"webcast_live"`
+	Location      string                `json:"location" bson:"location"`
+	NewsURL       string                `json:"news_url" bson:"news_url"`
+	VideoURL      string                `json:"video_url" bson:"video_url"`
+	Image         LL2Image              `json:"image" bson:"image"`
+	Date          string                `json:"date" bson:"date"`
+	DatePrecision LL2NetPrecision       `json:"date_precision" bson:"date_precision"`
+	Duration      string                `json:"duration" bson:"duration"`
+	Agencies      []LL2AgencyMini       `json:"agencies" bson:"agencies"`
+	LastUpdated   string                `json:"last_updated" bson:"last_updated"`
+	InfoURLs      []LL2InfoURL          `json:"info_urls" bson:"info_urls"`
+	VidURLs       []LL2VidURL           `json:"vid_urls" bson:"vid_urls"`
+	Expeditions   []LL2ExpeditionMini   `json:"expeditions" bson:"expeditions"`
+	Spacestations []LL2SpaceStationMini `json:"spacestations" bson:"spacestations"`
+	// Launches links the related launches by id, the launches collection has their details
+	Launches     []LL2LaunchBasic `json:"launches" bson:"launches"`
+	LL2Tombstone `bson:",inline"`
+}
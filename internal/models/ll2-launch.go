@@ -2,12 +2,7 @@
 // https://ll.thespacedevs.com/2.3.0/json
 package models
 
-type LL2Response struct {
-	Count    int                  `json:"count"`
-	Next     string               `json:"next"`
-	Previous string               `json:"previous"`
-	Results  []*LL2LaunchDetailed `json:"results"`
-}
+import "encoding/json"
 
 type LL2LaunchBasic struct {
 	ID               string          `json:"id" bson:"id"`
@@ -67,6 +62,8 @@ type LL2NetPrecision struct {
 
 type LL2LaunchNormal struct {
 	LL2LaunchBasic                 `bson:",inline"`
+	LL2Tombstone                   `bson:",inline"`
+	SlipCount                      int                `json:"slip_count" bson:"slip_count,omitempty"` // times net changed, counted by our syncs
 	Probability                    int                `json:"probability" bson:"probability"`
 	WeatherConcerns                string             `json:"weather_concerns" bson:"weather_concerns"`
 	FailReason                     string             `json:"failreason" bson:"failreason"`
@@ -90,6 +87,8 @@ type LL2LaunchNormal struct {
 type LL2RocketNormal struct {
 	ID            int                   `json:"id" bson:"id"`
 	Configuration LL2LauncherConfigList `json:"configuration" bson:"configuration"`
+	// SpacecraftStage is only returned by detailed mode
+	SpacecraftStage []LL2SpacecraftStage `json:"spacecraft_stage,omitempty" bson:"spacecraft_stage,omitempty"`
 }
 
 type LL2Mission struct {
@@ -117,6 +116,7 @@ type LL2CelestialBodyMini struct {
 	Name         string `json:"name" bson:"name"`
 }
 
+// LL2Country is served with the alpha2_code and alpha3_code keys our API has always used
 type LL2Country struct {
 	ID                      int    `json:"id" bson:"id"`
 	Name                    string `json:"name" bson:"name"`
@@ -126,6 +126,23 @@ type LL2Country struct {
 	NationalityNameComposed string `json:"nationality_name_composed" bson:"nationality_name_composed"`
 }
 
+// UnmarshalJSON reads the country codes from the alpha_2_code and alpha_3_code keys LL2 sends
+func (c *LL2Country) UnmarshalJSON(data []byte) error {
+	type country LL2Country
+	var v struct {
+		country
+		Alpha2Code string `json:"alpha_2_code"`
+		Alpha3Code string `json:"alpha_3_code"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = LL2Country(v.country)
+	c.Alpha2Code = v.Alpha2Code
+	c.Alpha3Code = v.Alpha3Code
+	return nil
+}
+
 type LL2SocialMediaLink struct {
 	ID          int            `json:"id" bson:"id"`
 	SocialMedia LL2SocialMedia `json:"social_media" bson:"social_media"`
@@ -19,6 +19,7 @@ type LL2LauncherConfigList struct {
 
 type LL2LauncherConfigNormal struct {
 	LL2LauncherConfigList `bson:",inline"`
+	LL2Tombstone          `bson:",inline"`
 	Active                bool               `json:"active" bson:"active"`
 	IsPlaceholder         bool               `json:"is_placeholder" bson:"is_placeholder"`
 	Manufacturer          LL2AgencyNormal    `json:"manufacturer" bson:"manufacturer"`
@@ -79,6 +80,7 @@ type LL2LauncherConfigFamilyNormal struct {
 
 type LL2LauncherConfigFamilyDetailed struct {
 	LL2LauncherConfigFamilyNormal `bson:",inline"`
+	LL2Tombstone                  `bson:",inline"`
 	Description                   string `json:"description" bson:"description"`
 	Active                        bool   `json:"active" bson:"active"`
 	MaidenFlight                  string `json:"maiden_flight" bson:"maiden_flight"`
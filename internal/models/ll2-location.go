@@ -29,6 +29,7 @@ type LL2PadSerializerNoLocation struct {
 
 type LL2Pad struct {
 	LL2PadSerializerNoLocation `bson:",inline"`
+	LL2Tombstone               `bson:",inline"`
 	Location                   LL2Location `json:"location" bson:"location"`
 }
 
@@ -58,6 +59,7 @@ type LL2Location struct {
 }
 
 type LL2LocationSerializerWithPads struct {
-	LL2Location `bson:",inline"`
-	Pads        []LL2PadSerializerNoLocation `json:"pads" bson:"pads"`
+	LL2Location  `bson:",inline"`
+	LL2Tombstone `bson:",inline"`
+	Pads         []LL2PadSerializerNoLocation `json:"pads" bson:"pads"`
 }
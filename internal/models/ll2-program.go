@@ -0,0 +1,6 @@
+package models
+
+type LL2ProgramDetailed struct {
+	LL2ProgramNormal `bson:",inline"`
+	LL2Tombstone     `bson:",inline"`
+}
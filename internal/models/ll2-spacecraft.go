@@ -0,0 +1,128 @@
+package models
+
+type LL2SpacecraftConfigType struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2SpacecraftConfigFamily struct {
+	ID           int    `json:"id" bson:"id"`
+	Name         string `json:"name" bson:"name"`
+	Description  string `json:"description" bson:"description"`
+	MaidenFlight string `json:"maiden_flight" bson:"maiden_flight"`
+}
+
+type LL2SpacecraftConfigMini struct {
+	ID           int    `json:"id" bson:"id"`
+	URL          string `json:"url" bson:"url"`
+	Name         string `json:"name" bson:"name"`
+	ResponseMode string `json:"response_mode" bson:"response_mode"`
+}
+
+type LL2SpacecraftConfigNormal struct {
+	LL2SpacecraftConfigMini `bson:",inline"`
+	Type                    LL2SpacecraftConfigType     `json:"type" bson:"type"`
+	Agency                  LL2AgencyMini               `json:"agency" bson:"agency"`
+	Family                  []LL2SpacecraftConfigFamily `json:"family" bson:"family"`
+	InUse                   bool                        `json:"in_use" bson:"in_use"`
+	Image                   LL2Image                    `json:"image" bson:"image"`
+}
+
+type LL2SpacecraftConfigDetailed struct {
+	LL2SpacecraftConfigNormal `bson:",inline"`
+	LL2Tombstone              `bson:",inline"`
+	Capability                string  `json:"capability" bson:"capability"`
+	History                   string  `json:"history" bson:"history"`
+	Details                   string  `json:"details" bson:"details"`
+	MaidenFlight              string  `json:"maiden_flight" bson:"maiden_flight"`
+	Height                    float64 `json:"height" bson:"height"`
+	Diameter                  float64 `json:"diameter" bson:"diameter"`
+	HumanRated                bool    `json:"human_rated" bson:"human_rated"`
+	CrewCapacity              int     `json:"crew_capacity" bson:"crew_capacity"`
+	PayloadCapacity           int     `json:"payload_capacity" bson:"payload_capacity"`
+	PayloadReturnCapacity     int     `json:"payload_return_capacity" bson:"payload_return_capacity"`
+	FlightLife                string  `json:"flight_life" bson:"flight_life"`
+	WikiLink                  string  `json:"wiki_link" bson:"wiki_link"`
+	InfoLink                  string  `json:"info_link" bson:"info_link"`
+	TotalLaunchCount          int     `json:"total_launch_count" bson:"total_launch_count"`
+	SuccessfulLaunches        int     `json:"successful_launches" bson:"successful_launches"`
+	FailedLaunches            int     `json:"failed_launches" bson:"failed_launches"`
+	AttemptedLandings         int     `json:"attempted_landings" bson:"attempted_landings"`
+	SuccessfulLandings        int     `json:"successful_landings" bson:"successful_landings"`
+	FailedLandings            int     `json:"failed_landings" bson:"failed_landings"`
+	FastestTurnaround         string  `json:"fastest_turnaround" bson:"fastest_turnaround"`
+}
+
+type LL2SpacecraftStatus struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2SpacecraftNormal struct {
+	ID                int                       `json:"id" bson:"id"`
+	URL               string                    `json:"url" bson:"url"`
+	Name              string                    `json:"name" bson:"name"`
+	SerialNumber      string                    `json:"serial_number" bson:"serial_number"`
+	IsPlaceholder     bool                      `json:"is_placeholder" bson:"is_placeholder"`
+	InSpace           bool                      `json:"in_space" bson:"in_space"`
+	TimeInSpace       string                    `json:"time_in_space" bson:"time_in_space"`
+	TimeDocked        string                    `json:"time_docked" bson:"time_docked"`
+	FlightsCount      int                       `json:"flights_count" bson:"flights_count"`
+	MissionEndsCount  int                       `json:"mission_ends_count" bson:"mission_ends_count"`
+	Status            LL2SpacecraftStatus       `json:"status" bson:"status"`
+	Description       string                    `json:"description" bson:"description"`
+	SpacecraftConfig  LL2SpacecraftConfigNormal `json:"spacecraft_config" bson:"spacecraft_config"`
+	FastestTurnaround string                    `json:"fastest_turnaround" bson:"fastest_turnaround"`
+}
+
+type LL2SpacecraftDetailed struct {
+	LL2SpacecraftNormal `bson:",inline"`
+	LL2Tombstone        `bson:",inline"`
+}
+
+type LL2AstronautRole struct {
+	ID       int    `json:"id" bson:"id"`
+	Role     string `json:"role" bson:"role"`
+	Priority int    `json:"priority" bson:"priority"`
+}
+
+// LL2AstronautFlight is an astronaut on board a spacecraft flight
+type LL2AstronautFlight struct {
+	ID        int                `json:"id" bson:"id"`
+	Role      LL2AstronautRole   `json:"role" bson:"role"`
+	Astronaut LL2AstronautNormal `json:"astronaut" bson:"astronaut"`
+}
+
+// LL2SpacecraftStage is a spacecraft flight as embedded in the rocket of its launch
+type LL2SpacecraftStage struct {
+	ID          int                  `json:"id" bson:"id"`
+	URL         string               `json:"url" bson:"url"`
+	Destination string               `json:"destination" bson:"destination"`
+	MissionEnd  string               `json:"mission_end" bson:"mission_end"`
+	Spacecraft  LL2SpacecraftNormal  `json:"spacecraft" bson:"spacecraft"`
+	Landing     LL2Landing           `json:"landing" bson:"landing"`
+	LaunchCrew  []LL2AstronautFlight `json:"launch_crew" bson:"launch_crew"`
+}
+
+type LL2Landing struct {
+	ID          int    `json:"id" bson:"id"`
+	URL         string `json:"url" bson:"url"`
+	Attempt     bool   `json:"attempt" bson:"attempt"`
+	Success     bool   `json:"success" bson:"success"`
+	Description string `json:"description" bson:"description"`
+}
+
+type LL2SpacecraftFlightDetailed struct {
+	ID          int                 `json:"id" bson:"id"`
+	URL         string              `json:"url" bson:"url"`
+	Destination string              `json:"destination" bson:"destination"`
+	MissionEnd  string              `json:"mission_end" bson:"mission_end"`
+	Spacecraft  LL2SpacecraftNormal `json:"spacecraft" bson:"spacecraft"`
+	// Launch links the launch the spacecraft rode on, the launches collection has its details
+	Launch       LL2LaunchBasic       `json:"launch" bson:"launch"`
+	Landing      LL2Landing           `json:"landing" bson:"landing"`
+	LaunchCrew   []LL2AstronautFlight `json:"launch_crew" bson:"launch_crew"`
+	OnboardCrew  []LL2AstronautFlight `json:"onboard_crew" bson:"onboard_crew"`
+	LandingCrew  []LL2AstronautFlight `json:"landing_crew" bson:"landing_crew"`
+	LL2Tombstone `bson:",inline"`
+}
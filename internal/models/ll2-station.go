@@ -0,0 +1,82 @@
+package models
+
+type LL2SpaceStationStatus struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2SpaceStationType struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2SpaceStationMini struct {
+	ID     int                   `json:"id" bson:"id"`
+	URL    string                `json:"url" bson:"url"`
+	Name   string                `json:"name" bson:"name"`
+	Image  LL2Image              `json:"image" bson:"image"`
+	Status LL2SpaceStationStatus `json:"status" bson:"status"`
+	Orbit  string                `json:"orbit" bson:"orbit"`
+}
+
+type LL2DockingLocation struct {
+	ID   int    `json:"id" bson:"id"`
+	Name string `json:"name" bson:"name"`
+}
+
+type LL2SpaceStationDetailed struct {
+	LL2SpaceStationMini `bson:",inline"`
+	LL2Tombstone        `bson:",inline"`
+	Type                LL2SpaceStationType  `json:"type" bson:"type"`
+	Founded             string               `json:"founded" bson:"founded"`
+	Deorbited           string               `json:"deorbited" bson:"deorbited"`
+	Description         string               `json:"description" bson:"description"`
+	Owners              []LL2AgencyNormal    `json:"owners" bson:"owners"`
+	Height              float64              `json:"height" bson:"height"`
+	Width               float64              `json:"width" bson:"width"`
+	Mass                float64              `json:"mass" bson:"mass"`
+	Volume              int                  `json:"volume" bson:"volume"`
+	OnboardCrew         int                  `json:"onboard_crew" bson:"onboard_crew"`
+	DockedVehicles      int                  `json:"docked_vehicles" bson:"docked_vehicles"`
+	DockingLocation     []LL2DockingLocation `json:"docking_location" bson:"docking_location"`
+}
+
+type LL2ExpeditionMini struct {
+	ID    int    `json:"id" bson:"id"`
+	URL   string `json:"url" bson:"url"`
+	Name  string `json:"name" bson:"name"`
+	Start string `json:"start" bson:"start"`
+	End   string `json:"end" bson:"end"`
+}
+
+type LL2ExpeditionDetailed struct {
+	ID           int                  `json:"id" bson:"id"`
+	URL          string               `json:"url" bson:"url"`
+	Name         string               `json:"name" bson:"name"`
+	Start        string               `json:"start" bson:"start"`
+	End          string               `json:"end" bson:"end"`
+	Spacestation LL2SpaceStationMini  `json:"spacestation" bson:"spacestation"`
+	Crew         []LL2AstronautFlight `json:"crew" bson:"crew"`
+	LL2Tombstone `bson:",inline"`
+}
+
+// LL2SpacecraftFlightMini is a spacecraft flight referenced by a docking event,
+// the spacecraft-flights collection has its details
+type LL2SpacecraftFlightMini struct {
+	ID          int                 `json:"id" bson:"id"`
+	URL         string              `json:"url" bson:"url"`
+	Destination string              `json:"destination" bson:"destination"`
+	MissionEnd  string              `json:"mission_end" bson:"mission_end"`
+	Spacecraft  LL2SpacecraftNormal `json:"spacecraft" bson:"spacecraft"`
+}
+
+type LL2DockingEventDetailed struct {
+	ID                  int                     `json:"id" bson:"id"`
+	URL                 string                  `json:"url" bson:"url"`
+	Docking             string                  `json:"docking" bson:"docking"`
+	Departure           string                  `json:"departure" bson:"departure"`
+	FlightVehicleChaser LL2SpacecraftFlightMini `json:"flight_vehicle_chaser" bson:"flight_vehicle_chaser"`
+	SpaceStationTarget  LL2SpaceStationMini     `json:"space_station_target" bson:"space_station_target"`
+	DockingLocation     LL2DockingLocation      `json:"docking_location" bson:"docking_location"`
+	LL2Tombstone        `bson:",inline"`
+}
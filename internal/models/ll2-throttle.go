@@ -0,0 +1,10 @@
+package models
+
+// LL2Throttle is the request budget of the caller, returned by /api-throttle/
+type LL2Throttle struct {
+	YourRequestLimit   int    `json:"your_request_limit"`
+	LimitFrequencySecs int    `json:"limit_frequency_secs"`
+	CurrentUse         int    `json:"current_use"`
+	NextUseSecs        int    `json:"next_use_secs"`
+	Ident              string `json:"ident"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// LL2Tombstone marks a record that was removed upstream,
+// it is kept in DB but excluded from lists by default
+type LL2Tombstone struct {
+	RemovedAt *time.Time `json:"removed_at,omitempty" bson:"removed_at,omitempty"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -41,3 +43,22 @@ func TestCreateRocketLaunchRequest(t *testing.T) {
 		t.Errorf("Expected status 'scheduled', got '%s'", req.Status)
 	}
 }
+
+func TestLL2CountryCodes(t *testing.T) {
+	var country LL2Country
+	if err := json.Unmarshal([]byte(`{"id": 2, "alpha_2_code": "US", "alpha_3_code": "USA"}`), &country); err != nil {
+		t.Fatalf("Failed to decode LL2 country: %v", err)
+	}
+	if country.ID != 2 || country.Alpha2Code != "US" || country.Alpha3Code != "USA" {
+		t.Errorf("Expected country 2 US/USA, got %+v", country)
+	}
+
+	// responses keep the keys clients already read
+	data, err := json.Marshal(country)
+	if err != nil {
+		t.Fatalf("Failed to encode country: %v", err)
+	}
+	if !strings.Contains(string(data), `"alpha2_code":"US"`) || !strings.Contains(string(data), `"alpha3_code":"USA"`) {
+		t.Errorf("Expected alpha2_code and alpha3_code keys, got %s", data)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vamosdalian/launchdate-backend/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// resolveAgencyLists fills the launcher_list and spacecraft_list of agencies that LL2 returned
+// without them from the stored launcher and spacecraft configurations the agencies build
+func resolveAgencyLists(ctx context.Context, s *LL2Service, docs []bson.M) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ids := agencyIDsMissing(docs, "launcher_list")
+	if len(ids) > 0 {
+		var launchers []models.LL2LauncherConfigNormal
+		if err := s.findAll(ctx, "ll2_launcher", map[string]any{"manufacturer.id": map[string]any{"$in": ids}}, &launchers); err != nil {
+			return err
+		}
+		byAgency := map[int][]any{}
+		for _, l := range launchers {
+			byAgency[l.Manufacturer.ID] = append(byAgency[l.Manufacturer.ID], l.LL2LauncherConfigList)
+		}
+		if err := setAgencyList(docs, "launcher_list", byAgency); err != nil {
+			return err
+		}
+	}
+
+	ids = agencyIDsMissing(docs, "spacecraft_list")
+	if len(ids) > 0 {
+		var configs []models.LL2SpacecraftConfigNormal
+		if err := s.findAll(ctx, "ll2_spacecraft_config", map[string]any{"agency.id": map[string]any{"$in": ids}}, &configs); err != nil {
+			return err
+		}
+		byAgency := map[int][]any{}
+		for _, c := range configs {
+			byAgency[c.Agency.ID] = append(byAgency[c.Agency.ID], c.LL2SpacecraftConfigMini)
+		}
+		if err := setAgencyList(docs, "spacecraft_list", byAgency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// agencyIDsMissing returns the ids of the agencies whose field is empty
+func agencyIDsMissing(docs []bson.M, field string) []int {
+	ids := []int{}
+	for _, doc := range docs {
+		if list, _ := doc[field].(bson.A); len(list) == 0 {
+			ids = append(ids, asInt(doc["id"]))
+		}
+	}
+	return ids
+}
+
+// setAgencyList sets field of the agencies missing it to their resolved list
+func setAgencyList(docs []bson.M, field string, byAgency map[int][]any) error {
+	for _, doc := range docs {
+		if list, _ := doc[field].(bson.A); len(list) > 0 {
+			continue
+		}
+		list := bson.A{}
+		for _, item := range byAgency[asInt(doc["id"])] {
+			link, err := toBSON(item)
+			if err != nil {
+				return err
+			}
+			list = append(list, link)
+		}
+		doc[field] = list
+	}
+	return nil
+}
+
+// findAll decodes every not removed document of collection matching filter into items
+func (s *LL2Service) findAll(ctx context.Context, collection string, filter map[string]any, items any) error {
+	filter[removedAtField] = map[string]any{"$exists": false}
+	cursor, err := s.mongoClient.Collection(collection).Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, items)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/util"
+)
+
+func TestTokenIsSentAsHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Token secret-token", req.Header.Get("Authorization"))
+		rw.Write([]byte(`{"your_request_limit": 300, "limit_frequency_secs": 3600}`))
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2APIToken: "secret-token"}, nil)
+	_, err := s.LoadThrottle(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestTokenIsRedactedFromErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// e.g. a proxy authenticating by path
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL + "/secret-token", LL2APIToken: "secret-token"}, nil)
+	_, err := s.LoadThrottle(context.Background())
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+	assert.Contains(t, err.Error(), "[REDACTED]")
+
+	s = NewLL2Service(&config.Config{LL2URLPrefix: "http://127.0.0.1:0/secret-token", LL2APIToken: "secret-token"}, nil)
+	_, err = s.LoadThrottle(context.Background())
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestRequestIntervalFollowsTokenTier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"your_request_limit": 3600, "limit_frequency_secs": 3600}`))
+	}))
+	defer server.Close()
+
+	// no interval configured, the budget of the token is followed even without adaptive rate limiting
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2APIToken: "secret-token"}, nil)
+	assert.Equal(t, defaultRequestInterval, s.LL2RequestInterval)
+	rl := util.NewAdaptiveRateLimit(time.Duration(s.LL2RequestInterval) * time.Second)
+	defer rl.Close()
+	s.adaptRate(context.Background(), rl)
+	assert.Equal(t, time.Second, rl.Interval())
+
+	// a configured interval is kept
+	s = NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2APIToken: "secret-token", LL2RequestInterval: 2}, nil)
+	rl.SetInterval(2 * time.Second)
+	s.adaptRate(context.Background(), rl)
+	assert.Equal(t, 2*time.Second, rl.Interval())
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PageResult reports what writing a single page of LL2 results did
+type PageResult struct {
+	Matched  int64 `json:"matched"`
+	Modified int64 `json:"modified"`
+	Upserted int64 `json:"upserted"`
+}
+
+// upsertModel returns a write model that sets doc on the document whose idField equals id,
+// inserting it if it does not exist yet. A document seen again is no longer marked as removed.
+func upsertModel(idField string, id any, doc any) mongo.WriteModel {
+	return mongo.NewUpdateOneModel().
+		SetFilter(map[string]any{idField: id}).
+		SetUpdate(map[string]any{
+			"$set":   doc,
+			"$unset": map[string]any{removedAtField: ""},
+		}).
+		SetUpsert(true)
+}
+
+// bulkUpsert writes a page in a single unordered BulkWrite
+func (s *LL2Service) bulkUpsert(ctx context.Context, collection string, writes []mongo.WriteModel) (PageResult, error) {
+	if len(writes) == 0 {
+		return PageResult{}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	opts := options.BulkWrite().SetOrdered(false)
+	res, err := s.mongoClient.Collection(collection).BulkWrite(ctx, writes, opts)
+	if err != nil {
+		return PageResult{}, err
+	}
+	return PageResult{
+		Matched:  res.MatchedCount,
+		Modified: res.ModifiedCount,
+		Upserted: res.UpsertedCount,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const ChangeCollection = "changes"
+
+// untrackedFields are written by the sync loop itself rather than by LL2, their changes are not logged
+var untrackedFields = map[string]bool{
+	"_id":          true,
+	syncRunField:   true,
+	removedAtField: true,
+	slipCountField: true,
+}
+
+// Change records a field of a synced document whose value changed
+type Change struct {
+	Entity   string    `json:"entity" bson:"entity"` // name of the resource, e.g. "agencies"
+	EntityID any       `json:"entity_id" bson:"entity_id"`
+	Path     string    `json:"path" bson:"path"` // dotted field path, e.g. "status.name"
+	Old      any       `json:"old" bson:"old"`
+	New      any       `json:"new" bson:"new"`
+	SyncedAt time.Time `json:"synced_at" bson:"synced_at"`
+}
+
+// ChangeQuery selects changes from the change log, zero fields do not filter
+type ChangeQuery struct {
+	Entity   string
+	EntityID string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+	Offset   int
+}
+
+// recordChanges diffs docs against their stored version and logs every changed field path.
+// New documents are not logged.
+func (s *LL2Service) recordChanges(ctx context.Context, r *Resource, docs []bson.M, stored map[any]bson.M, now time.Time) (int, error) {
+	changes := []any{}
+	for _, doc := range docs {
+		old, ok := stored[doc[r.IDField]]
+		if !ok {
+			continue
+		}
+		for _, c := range diffDocs("", old, doc) {
+			c.Entity = r.Name
+			c.EntityID = doc[r.IDField]
+			c.SyncedAt = now
+			changes = append(changes, c)
+		}
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	opts := options.InsertMany().SetOrdered(false)
+	if _, err := s.mongoClient.Collection(ChangeCollection).InsertMany(ctx, changes, opts); err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// diffDocs returns the paths below prefix whose value differs between old and new.
+// Embedded documents are compared field by field, arrays as a whole.
+// Fields missing from new are kept by the upsert, so they are not changes.
+func diffDocs(prefix string, old, new bson.M) []Change {
+	keys := make([]string, 0, len(new))
+	for key := range new {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		if prefix == "" && untrackedFields[key] {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		oldValue, newValue := old[key], new[key]
+		oldDoc, oldIsDoc := oldValue.(bson.M)
+		newDoc, newIsDoc := newValue.(bson.M)
+		if oldIsDoc && newIsDoc {
+			changes = append(changes, diffDocs(path, oldDoc, newDoc)...)
+			continue
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// Changes returns the logged changes matching q, newest first
+func (s *LL2Service) Changes(ctx context.Context, q ChangeQuery) ([]Change, error) {
+	filter := map[string]any{}
+	if q.Entity != "" {
+		if _, ok := LookupResource(q.Entity); !ok {
+			return nil, fmt.Errorf("unknown LL2 resource %q", q.Entity)
+		}
+		filter["entity"] = q.Entity
+	}
+	if q.EntityID != "" {
+		filter["entity_id"] = parseID(q.EntityID)
+	}
+	syncedAt := map[string]any{}
+	if !q.Since.IsZero() {
+		syncedAt["$gte"] = q.Since
+	}
+	if !q.Until.IsZero() {
+		syncedAt["$lt"] = q.Until
+	}
+	if len(syncedAt) > 0 {
+		filter["synced_at"] = syncedAt
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(q.Limit))
+	findOptions.SetSkip(int64(q.Offset))
+	findOptions.SetSort(map[string]int{"synced_at": -1})
+
+	cursor, err := s.valueCollection(ChangeCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []Change{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDiffDocs(t *testing.T) {
+	old := bson.M{
+		"id":                 int32(225),
+		"name":               "SpaceX",
+		"total_launch_count": int32(400),
+		"country":            bson.A{bson.M{"id": int32(1)}},
+		"type":               bson.M{"id": int32(3), "name": "Commercial"},
+		"description":        "kept when missing from the new version",
+		"slip_count":         int32(2),
+	}
+	new := bson.M{
+		"id":                 int32(225),
+		"name":               "SpaceX",
+		"total_launch_count": int32(401),
+		"country":            bson.A{bson.M{"id": int32(1)}, bson.M{"id": int32(2)}},
+		"type":               bson.M{"id": int32(3), "name": "Private"},
+		"founding_year":      int32(2002),
+		"slip_count":         int32(3),
+		syncRunField:         "run",
+	}
+
+	changes := diffDocs("", old, new)
+	paths := []string{}
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	assert.Equal(t, []string{"country", "founding_year", "total_launch_count", "type.name"}, paths)
+	assert.Equal(t, Change{Path: "type.name", Old: "Commercial", New: "Private"}, changes[3])
+	assert.Nil(t, changes[1].Old)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CheckpointCollection = "ll2_sync_checkpoint"
+
+// SyncCheckpoint records the last page a sync of a resource committed,
+// so a restarted or retried sync continues from there instead of offset 0
+type SyncCheckpoint struct {
+	Resource  string    `json:"resource" bson:"resource"`
+	Offset    int       `json:"offset" bson:"offset"`
+	Pages     int       `json:"pages" bson:"pages"`
+	Cursor    string    `json:"cursor,omitempty" bson:"cursor,omitempty"` // encoded LL2 filter of an incremental sync, its lower bound advances page by page
+	RunID     string    `json:"run_id" bson:"run_id"`                     // identifies the run across restarts
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// loadCheckpoint returns the stored checkpoint of resource,
+// or an empty one starting at offset 0 if there is none
+func (s *LL2Service) loadCheckpoint(ctx context.Context, resource string) (*SyncCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cp := &SyncCheckpoint{Resource: resource}
+	err := s.mongoClient.Collection(CheckpointCollection).FindOne(ctx, map[string]any{"resource": resource}).Decode(cp)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &SyncCheckpoint{Resource: resource}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// commitPage advances cp to offset and persists it
+func (s *LL2Service) commitPage(ctx context.Context, cp *SyncCheckpoint, offset int) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cp.Offset = offset
+	cp.Pages++
+	cp.UpdatedAt = time.Now()
+	filter := map[string]any{"resource": cp.Resource}
+	update := map[string]any{"$set": cp}
+	opts := options.Update().SetUpsert(true)
+	_, err := s.mongoClient.Collection(CheckpointCollection).UpdateOne(ctx, filter, update, opts)
+	return err
+}
+
+// GetCheckpoints returns the checkpoints of all unfinished syncs
+func (s *LL2Service) GetCheckpoints(ctx context.Context) ([]SyncCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := s.mongoClient.Collection(CheckpointCollection).Find(ctx, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	checkpoints := []SyncCheckpoint{}
+	if err := cursor.All(ctx, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// ClearCheckpoint removes the checkpoint of the named resource on request.
+// It refuses while a sync of the resource is running, as that sync would write its checkpoint again.
+func (s *LL2Service) ClearCheckpoint(ctx context.Context, name string) error {
+	r, ok := LookupResource(name)
+	if !ok {
+		return fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	if id, ok := s.jobs.Running(r.Name); ok {
+		return fmt.Errorf("%w: %s", ErrJobRunning, id)
+	}
+	return s.ResetCheckpoint(ctx, r.Name)
+}
+
+// ResetCheckpoint removes the checkpoint of resource, so its next sync starts from offset 0.
+// It is also called when a sync completes.
+func (s *LL2Service) ResetCheckpoint(ctx context.Context, resource string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := s.mongoClient.Collection(CheckpointCollection).DeleteOne(ctx, map[string]any{"resource": resource})
+	return err
+}
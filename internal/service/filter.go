@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FilterKind selects how the value of a list query parameter is matched
+type FilterKind int
+
+const (
+	// FilterObject matches an embedded LL2 object by its numeric id, or case-insensitively by Name
+	FilterObject FilterKind = iota
+	// FilterEqual matches the field exactly, numeric values as ints
+	FilterEqual
+	// FilterAfter matches RFC 3339 times at or after the value
+	FilterAfter
+	// FilterBefore matches RFC 3339 times before the value
+	FilterBefore
+	// FilterText matches a string field case-insensitively
+	FilterText
+	// FilterNonEmpty matches an array field being non-empty for true, or empty for false
+	FilterNonEmpty
+)
+
+// Filter declares a list query parameter of a resource
+type Filter struct {
+	Field string
+	Kind  FilterKind
+	// Name is the field FilterObject matches non-numeric values on, "name" if empty
+	Name string
+}
+
+// buildFilter translates the values of the declared Filters of r into a Mongo filter,
+// empty values are ignored and undeclared filters rejected
+func buildFilter(r *Resource, values map[string]string) (map[string]any, error) {
+	filter := map[string]any{}
+	for param, value := range values {
+		f, ok := r.Filters[param]
+		if !ok {
+			return nil, fmt.Errorf("LL2 resource %s can not be filtered by %s", r.Name, param)
+		}
+		if value == "" {
+			continue
+		}
+		switch f.Kind {
+		case FilterObject:
+			path, match := objectFilter(f.Field, f.Name, value)
+			filter[path] = match
+		case FilterEqual:
+			filter[f.Field] = parseID(value)
+		case FilterText:
+			filter[f.Field] = textMatch(value)
+		case FilterNonEmpty:
+			nonEmpty, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+			filter[f.Field+".0"] = map[string]any{"$exists": nonEmpty}
+		case FilterAfter, FilterBefore:
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+			op := "$gte"
+			if f.Kind == FilterBefore {
+				op = "$lt"
+			}
+			// LL2 times are stored as UTC RFC 3339 strings, which sort chronologically
+			rangeFilter, _ := filter[f.Field].(map[string]any)
+			if rangeFilter == nil {
+				rangeFilter = map[string]any{}
+				filter[f.Field] = rangeFilter
+			}
+			rangeFilter[op] = t.UTC().Format(time.RFC3339)
+		}
+	}
+	return filter, nil
+}
+
+// objectFilter matches an embedded LL2 object by its numeric id, or case-insensitively by its name field
+func objectFilter(field, name, value string) (string, any) {
+	if id, err := strconv.Atoi(value); err == nil {
+		return field + ".id", id
+	}
+	if name == "" {
+		name = "name"
+	}
+	return field + "." + name, textMatch(value)
+}
+
+// textMatch matches a string equal to value ignoring case
+func textMatch(value string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestObjectFilter(t *testing.T) {
+	path, match := objectFilter("agency", "", "44")
+	assert.Equal(t, "agency.id", path)
+	assert.Equal(t, 44, match)
+
+	path, match = objectFilter("status", "", "Active")
+	assert.Equal(t, "status.name", path)
+	assert.Equal(t, primitive.Regex{Pattern: "^Active$", Options: "i"}, match)
+
+	path, match = objectFilter("mission.orbit", "abbrev", "LEO")
+	assert.Equal(t, "mission.orbit.abbrev", path)
+	assert.Equal(t, primitive.Regex{Pattern: "^LEO$", Options: "i"}, match)
+}
+
+func TestBuildFilter(t *testing.T) {
+	r, _ := LookupResource("events")
+
+	filter, err := buildFilter(r, map[string]string{
+		"type":        "Spacewalk",
+		"launch":      "e3df2ecd-c239-472f-95e4-2b89b4f75800",
+		"date_after":  "2024-01-01T00:00:00+08:00",
+		"date_before": "2024-02-01T00:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"type.name":   primitive.Regex{Pattern: "^Spacewalk$", Options: "i"},
+		"launches.id": "e3df2ecd-c239-472f-95e4-2b89b4f75800",
+		"date": map[string]any{
+			"$gte": "2023-12-31T16:00:00Z",
+			"$lt":  "2024-02-01T00:00:00Z",
+		},
+	}, filter)
+
+	_, err = buildFilter(r, map[string]string{"date_after": "yesterday"})
+	assert.Error(t, err)
+
+	_, err = buildFilter(r, map[string]string{"agency": "44"})
+	assert.Error(t, err)
+
+	// an empty value does not filter, but an undeclared parameter is rejected even without value
+	filter, err = buildFilter(r, map[string]string{"type": ""})
+	assert.NoError(t, err)
+	assert.Empty(t, filter)
+	_, err = buildFilter(r, map[string]string{"agency": ""})
+	assert.Error(t, err)
+}
+
+func TestBuildLaunchFilter(t *testing.T) {
+	r, _ := LookupResource("launches")
+
+	filter, err := buildFilter(r, map[string]string{
+		"lsp":          "121",
+		"rocket":       "Falcon 9",
+		"family":       "1",
+		"pad":          "80",
+		"location":     "12",
+		"country":      "usa",
+		"status":       "Go",
+		"orbit":        "LEO",
+		"program":      "17",
+		"mission_type": "Human Exploration",
+		"net_after":    "2025-01-01T00:00:00Z",
+		"net_before":   "2025-02-01T00:00:00Z",
+		"crewed":       "true",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"launch_service_provider.id":            121,
+		"rocket.configuration.name":             primitive.Regex{Pattern: "^Falcon 9$", Options: "i"},
+		"rocket.configuration.families.id":      1,
+		"pad.id":                                80,
+		"pad.location.id":                       12,
+		"pad.country.alpha3_code":               primitive.Regex{Pattern: "^usa$", Options: "i"},
+		"status.abbrev":                         primitive.Regex{Pattern: "^Go$", Options: "i"},
+		"mission.orbit.abbrev":                  primitive.Regex{Pattern: "^LEO$", Options: "i"},
+		"program.id":                            17,
+		"mission.type":                          primitive.Regex{Pattern: "^Human Exploration$", Options: "i"},
+		"rocket.spacecraft_stage.launch_crew.0": map[string]any{"$exists": true},
+		"net": map[string]any{
+			"$gte": "2025-01-01T00:00:00Z",
+			"$lt":  "2025-02-01T00:00:00Z",
+		},
+	}, filter)
+
+	_, err = buildFilter(r, map[string]string{"crewed": "yes"})
+	assert.Error(t, err)
+
+	_, err = buildFilter(r, map[string]string{"net_before": "2025-02-01"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// slipCountField counts how often the SlipField of a record changed
+const slipCountField = "slip_count"
+
+// HistoryEntry records a change of a tracked field of a record seen by a sync
+type HistoryEntry struct {
+	ID       any       `json:"id" bson:"id"` // id of the changed record
+	Field    string    `json:"field" bson:"field"`
+	Old      any       `json:"old" bson:"old"`
+	New      any       `json:"new" bson:"new"`
+	SyncedAt time.Time `json:"synced_at" bson:"synced_at"`
+}
+
+// historyCollection returns the collection the history of r is recorded in
+func historyCollection(r *Resource) string {
+	return r.Collection + "_history"
+}
+
+// recordHistory compares the HistoryFields of docs with their stored version and records every change.
+// Docs whose SlipField changed get their slip count increased, new docs have no history yet.
+// It runs before docs are written, so a page retried after a failed write may record a change twice
+// rather than losing it.
+func (s *LL2Service) recordHistory(ctx context.Context, r *Resource, docs []bson.M, stored map[any]bson.M, now time.Time) (int, error) {
+	if len(r.HistoryFields) == 0 {
+		return 0, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	entries := []any{}
+	for _, doc := range docs {
+		old, ok := stored[doc[r.IDField]]
+		if !ok {
+			continue
+		}
+		for _, field := range r.HistoryFields {
+			if reflect.DeepEqual(old[field], doc[field]) {
+				continue
+			}
+			entries = append(entries, HistoryEntry{
+				ID:       doc[r.IDField],
+				Field:    field,
+				Old:      old[field],
+				New:      doc[field],
+				SyncedAt: now,
+			})
+			if field == r.SlipField {
+				doc[slipCountField] = asInt(old[slipCountField]) + 1
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	opts := options.InsertMany().SetOrdered(false)
+	if _, err := s.mongoClient.Collection(historyCollection(r)).InsertMany(ctx, entries, opts); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// findStored returns the stored version of docs keyed by id
+func (s *LL2Service) findStored(ctx context.Context, r *Resource, docs []bson.M) (map[any]bson.M, error) {
+	if len(docs) == 0 {
+		return map[any]bson.M{}, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	ids := make([]any, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc[r.IDField])
+	}
+
+	opts := options.Find().SetProjection(map[string]int{"_id": 0})
+	cursor, err := s.mongoClient.Collection(r.Collection).Find(ctx, map[string]any{r.IDField: map[string]any{"$in": ids}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []bson.M
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	stored := make(map[any]bson.M, len(found))
+	for _, doc := range found {
+		stored[doc[r.IDField]] = doc
+	}
+	return stored, nil
+}
+
+// History returns the recorded changes of the record of the named resource with the given id, oldest first
+func (s *LL2Service) History(ctx context.Context, name, id string, opts ListOptions) ([]HistoryEntry, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	if len(r.HistoryFields) == 0 {
+		return nil, fmt.Errorf("LL2 resource %s has no history", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.Limit))
+	findOptions.SetSkip(int64(opts.Offset))
+	findOptions.SetSort(map[string]int{"synced_at": 1})
+
+	cursor, err := s.valueCollection(historyCollection(r)).Find(ctx, map[string]any{"id": parseID(id)}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []HistoryEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// valueCollection returns the named collection of history or change entries,
+// their old and new values are decoded as maps rather than key/value lists
+func (s *LL2Service) valueCollection(name string) *mongo.Collection {
+	return s.mongoClient.Collection(name, options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true}))
+}
+
+// parseID converts an id given in a URL to the type LL2 uses, numeric ids are ints and all others strings
+func parseID(id string) any {
+	if n, err := strconv.Atoi(id); err == nil {
+		return n
+	}
+	return id
+}
+
+// asInt returns a number read from DB as int, 0 if v is not a number
+func asInt(v any) int {
+	switch n := v.(type) {
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case int:
+		return n
+	case float64:
+		return int(n)
+	}
+	return 0
+}
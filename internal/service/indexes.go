@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// EnsureIndexes creates the indexes the change log and history queries rely on,
+// indexes that already exist are left as they are
+func (s *LL2Service) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := s.mongoClient.Collection(ChangeCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "synced_at", Value: -1}}},
+		{Keys: bson.D{{Key: "synced_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, r := range resources {
+		if len(r.HistoryFields) == 0 {
+			continue
+		}
+		_, err := s.mongoClient.Collection(historyCollection(r)).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "id", Value: 1}, {Key: "synced_at", Value: 1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestEnsureIndexes(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	s := NewLL2Service(&config.Config{}, mongoDB)
+	assert.NoError(t, s.EnsureIndexes(context.Background()))
+	// creating them again is a no-op
+	assert.NoError(t, s.EnsureIndexes(context.Background()))
+
+	indexNames := func(collection string) []string {
+		cursor, err := mongoDB.Collection(collection).Indexes().List(context.Background())
+		assert.NoError(t, err)
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		names := []string{}
+		for _, index := range indexes {
+			names = append(names, index["name"].(string))
+		}
+		return names
+	}
+	assert.ElementsMatch(t, []string{"_id_", "entity_1_entity_id_1_synced_at_-1", "synced_at_-1"}, indexNames(ChangeCollection))
+	assert.ElementsMatch(t, []string{"_id_", "id_1_synced_at_1"}, indexNames(LL2COLLECTION+"_history"))
+}
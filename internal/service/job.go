@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// JobStatus describes where a sync job is in its lifecycle
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+	JobCanceled  JobStatus = "canceled"
+)
+
+// maxFinishedJobs bounds how many finished jobs are kept in memory
+const maxFinishedJobs = 100
+
+var (
+	ErrJobRunning    = errors.New("a sync job for this resource is already running")
+	ErrJobNotFound   = errors.New("job not found")
+	ErrJobNotRunning = errors.New("job is not running")
+	ErrShuttingDown  = errors.New("job manager is shutting down")
+)
+
+// Job is a snapshot of a single sync run
+type Job struct {
+	ID        string     `json:"id"`
+	Resource  string     `json:"resource"`
+	Status    JobStatus  `json:"status"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Pages     int        `json:"pages"`
+	Matched   int64      `json:"matched"`
+	Modified  int64      `json:"modified"`
+	Upserted  int64      `json:"upserted"`
+	Removed   int64      `json:"removed"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// JobTracker lets a running sync report its progress back to the manager
+type JobTracker struct {
+	m  *JobManager
+	id string
+}
+
+// JobManager runs sync jobs, allowing at most one running job per resource
+type JobManager struct {
+	mu       sync.Mutex
+	jobs     map[string]*Job
+	running  map[string]string             // resource -> job id
+	cancels  map[string]context.CancelFunc // job id -> cancel of a running job
+	wg       sync.WaitGroup
+	shutdown bool
+}
+
+func NewJobManager() *JobManager {
+	return &JobManager{
+		jobs:    make(map[string]*Job),
+		running: make(map[string]string),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Start registers a new job for resource and runs fn with a context that is canceled by Cancel or Shutdown.
+// if async is true, fn runs in background and the returned job is the initial snapshot,
+// it is then no longer bound to ctx, e.g. the request that started it.
+// otherwise fn runs synchronously and the final snapshot and error are returned.
+// ErrJobRunning is returned if a job for the same resource has not finished yet.
+func (m *JobManager) Start(ctx context.Context, resource string, async bool, fn func(ctx context.Context, t *JobTracker) error) (Job, error) {
+	m.mu.Lock()
+	if m.shutdown {
+		m.mu.Unlock()
+		return Job{}, ErrShuttingDown
+	}
+	if id, ok := m.running[resource]; ok {
+		m.mu.Unlock()
+		return Job{}, fmt.Errorf("%w: %s", ErrJobRunning, id)
+	}
+	job := &Job{
+		ID:        uuid.NewString(),
+		Resource:  resource,
+		Status:    JobRunning,
+		StartedAt: time.Now(),
+	}
+	if async {
+		ctx = context.WithoutCancel(ctx)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	m.jobs[job.ID] = job
+	m.running[resource] = job.ID
+	m.cancels[job.ID] = cancel
+	m.wg.Add(1)
+	snapshot := *job
+	m.mu.Unlock()
+
+	tracker := &JobTracker{m: m, id: job.ID}
+	run := func() error {
+		defer m.wg.Done()
+		defer cancel()
+		err := fn(ctx, tracker)
+		m.finish(job.ID, err)
+		if errors.Is(err, context.Canceled) {
+			logrus.Infof("LL2 %s sync job %s canceled", resource, job.ID)
+		} else if err != nil {
+			logrus.Errorf("LL2 %s sync job %s failed: %s", resource, job.ID, err)
+		}
+		return err
+	}
+
+	if async {
+		go run()
+		return snapshot, nil
+	}
+	err := run()
+	final, _ := m.Get(job.ID)
+	return final, err
+}
+
+// Get returns a snapshot of the job with the given id
+func (m *JobManager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return *job, nil
+}
+
+// Running returns the id of the running job of resource, if there is one
+func (m *JobManager) Running(resource string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, ok := m.running[resource]
+	return id, ok
+}
+
+// Cancel stops the running job with the given id, the job ends with status canceled
+func (m *JobManager) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+	cancel, ok := m.cancels[id]
+	if !ok {
+		return ErrJobNotRunning
+	}
+	cancel()
+	return nil
+}
+
+// Shutdown refuses new jobs, cancels all running ones and waits for them to return or ctx to be done
+func (m *JobManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.shutdown = true
+	for _, cancel := range m.cancels {
+		cancel()
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// List returns snapshots of all known jobs, newest first
+func (m *JobManager) List() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
+
+func (m *JobManager) finish(id string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := m.jobs[id]
+	now := time.Now()
+	job.EndedAt = &now
+	switch {
+	case err == nil:
+		job.Status = JobSucceeded
+	case errors.Is(err, context.Canceled):
+		job.Status = JobCanceled
+		job.Error = err.Error()
+	default:
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	delete(m.running, job.Resource)
+	delete(m.cancels, id)
+	m.prune()
+}
+
+// prune drops the oldest finished jobs once more than maxFinishedJobs are kept
+func (m *JobManager) prune() {
+	var finished []*Job
+	for _, job := range m.jobs {
+		if job.Status != JobRunning {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].StartedAt.Before(finished[j].StartedAt)
+	})
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		delete(m.jobs, job.ID)
+	}
+}
+
+// AddPage records one fetched page and the result of writing it
+func (t *JobTracker) AddPage(res PageResult) {
+	if t == nil {
+		return
+	}
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	if job, ok := t.m.jobs[t.id]; ok {
+		job.Pages++
+		job.Matched += res.Matched
+		job.Modified += res.Modified
+		job.Upserted += res.Upserted
+	}
+}
+
+// AddRemoved records documents marked as removed upstream
+func (t *JobTracker) AddRemoved(n int64) {
+	if t == nil {
+		return
+	}
+	t.m.mu.Lock()
+	defer t.m.mu.Unlock()
+	if job, ok := t.m.jobs[t.id]; ok {
+		job.Removed += n
+	}
+}
+
+// ID returns the id of the tracked job
+func (t *JobTracker) ID() string {
+	if t == nil {
+		return ""
+	}
+	return t.id
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+func TestJobManagerSingleFlight(t *testing.T) {
+	m := NewJobManager()
+	release := make(chan struct{})
+
+	first, err := m.Start(context.Background(), "launches", true, func(ctx context.Context, jt *JobTracker) error {
+		<-release
+		jt.AddPage(PageResult{Matched: 4, Modified: 2, Upserted: 6})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, JobRunning, first.Status)
+
+	_, err = m.Start(context.Background(), "launches", true, func(ctx context.Context, jt *JobTracker) error { return nil })
+	assert.ErrorIs(t, err, ErrJobRunning)
+
+	// a different resource is not blocked
+	other, err := m.Start(context.Background(), "pads", false, func(ctx context.Context, jt *JobTracker) error {
+		jt.AddPage(PageResult{Upserted: 3})
+		jt.AddPage(PageResult{Matched: 2, Modified: 1})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, JobSucceeded, other.Status)
+	assert.Equal(t, 2, other.Pages)
+	assert.Equal(t, int64(2), other.Matched)
+	assert.Equal(t, int64(1), other.Modified)
+	assert.Equal(t, int64(3), other.Upserted)
+	assert.NotNil(t, other.EndedAt)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		job, err := m.Get(first.ID)
+		return err == nil && job.Status == JobSucceeded
+	}, time.Second, 10*time.Millisecond)
+
+	job, _ := m.Get(first.ID)
+	assert.Equal(t, 1, job.Pages)
+	assert.Equal(t, int64(4), job.Matched)
+	assert.Equal(t, int64(6), job.Upserted)
+	assert.Len(t, m.List(), 2)
+}
+
+func TestJobManagerRecordsError(t *testing.T) {
+	m := NewJobManager()
+
+	job, err := m.Start(context.Background(), "agencies", false, func(ctx context.Context, jt *JobTracker) error {
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, JobFailed, job.Status)
+	assert.Equal(t, "boom", job.Error)
+
+	_, err = m.Get("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestJobManagerCancel(t *testing.T) {
+	m := NewJobManager()
+
+	job, err := m.Start(context.Background(), "launches", true, func(ctx context.Context, jt *JobTracker) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, m.Cancel(job.ID))
+	assert.Eventually(t, func() bool {
+		job, err = m.Get(job.ID)
+		return err == nil && job.Status == JobCanceled
+	}, time.Second, 10*time.Millisecond)
+
+	assert.ErrorIs(t, m.Cancel(job.ID), ErrJobNotRunning)
+	assert.ErrorIs(t, m.Cancel("missing"), ErrJobNotFound)
+}
+
+func TestJobManagerShutdown(t *testing.T) {
+	m := NewJobManager()
+
+	returned := make(chan struct{})
+	_, err := m.Start(context.Background(), "launches", true, func(ctx context.Context, jt *JobTracker) error {
+		<-ctx.Done()
+		close(returned)
+		return ctx.Err()
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, m.Shutdown(ctx))
+	select {
+	case <-returned:
+	default:
+		t.Fatal("Expected Shutdown to wait for the running job")
+	}
+
+	_, err = m.Start(context.Background(), "pads", false, func(ctx context.Context, jt *JobTracker) error { return nil })
+	assert.ErrorIs(t, err, ErrShuttingDown)
+}
+
+func TestClearCheckpointRefusesRunningSync(t *testing.T) {
+	s := NewLL2Service(&config.Config{}, nil)
+	release := make(chan struct{})
+	defer close(release)
+
+	job, err := s.Jobs().Start(context.Background(), "launches", true, func(ctx context.Context, jt *JobTracker) error {
+		<-release
+		return nil
+	})
+	assert.NoError(t, err)
+
+	id, ok := s.Jobs().Running("launches")
+	assert.True(t, ok)
+	assert.Equal(t, job.ID, id)
+
+	err = s.ClearCheckpoint(context.Background(), "launches")
+	assert.ErrorIs(t, err, ErrJobRunning)
+
+	err = s.ClearCheckpoint(context.Background(), "rockets")
+	assert.Error(t, err)
+}
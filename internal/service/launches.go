@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LL2 launch status ids
+const (
+	statusGo             = 1
+	statusTBD            = 2
+	statusSuccess        = 3
+	statusFailure        = 4
+	statusHold           = 5
+	statusInFlight       = 6
+	statusPartialFailure = 7
+	statusTBC            = 8
+)
+
+var (
+	// pendingStatuses have not launched yet or are still flying, whatever their NET
+	pendingStatuses = []int{statusGo, statusTBD, statusTBC, statusHold, statusInFlight}
+	// finishedStatuses have launched, whatever their NET
+	finishedStatuses = []int{statusSuccess, statusFailure, statusPartialFailure}
+)
+
+// ListUpcomingLaunches returns a page of the launches that are pending, or whose NET is not
+// in the past and that have not launched, soonest first
+func (s *LL2Service) ListUpcomingLaunches(ctx context.Context, opts ListOptions) (any, error) {
+	return s.listLaunchesAround(ctx, time.Now(), true, opts)
+}
+
+// ListPreviousLaunches returns a page of the launches that have launched, or whose NET is
+// in the past and that are not pending, most recent first
+func (s *LL2Service) ListPreviousLaunches(ctx context.Context, opts ListOptions) (any, error) {
+	return s.listLaunchesAround(ctx, time.Now(), false, opts)
+}
+
+// listLaunchesAround splits launches into upcoming and previous ones relative to now,
+// every launch is in exactly one of them
+func (s *LL2Service) listLaunchesAround(ctx context.Context, now time.Time, upcoming bool, opts ListOptions) (any, error) {
+	r, _ := LookupResource("launches")
+	filter, err := buildFilter(r, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	net := now.UTC().Format(time.RFC3339)
+	filter["$or"] = launchesAround(net, upcoming)
+	order := 1
+	if !upcoming {
+		order = -1
+	}
+	return s.list(ctx, r, filter, bson.D{{Key: "net", Value: order}, {Key: "id", Value: order}}, opts)
+}
+
+func launchesAround(net string, upcoming bool) []map[string]any {
+	if upcoming {
+		return []map[string]any{
+			{"status.id": map[string]any{"$in": pendingStatuses}},
+			{"net": map[string]any{"$gte": net}, "status.id": map[string]any{"$nin": finishedStatuses}},
+		}
+	}
+	return []map[string]any{
+		{"status.id": map[string]any{"$in": finishedStatuses}},
+		{"net": map[string]any{"$lt": net}, "status.id": map[string]any{"$nin": pendingStatuses}},
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/models"
+)
+
+func TestListLaunchesAround(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	launch := func(id, net string, status int) map[string]any {
+		return map[string]any{"id": id, "net": net, "status": map[string]any{"id": status}}
+	}
+	_, err := mongoDB.Collection(LL2COLLECTION).InsertMany(context.Background(), []any{
+		launch("sputnik", "1957-10-04T19:28:34Z", statusSuccess),
+		launch("failed", "2024-06-01T00:00:00Z", statusFailure),
+		launch("stale-tbd", "2024-12-01T00:00:00Z", statusTBD), // NET passed without an update
+		launch("next", "2025-01-02T00:00:00Z", statusGo),
+		launch("later", "2025-03-01T00:00:00Z", statusTBC),
+		launch("early-success", "2025-02-01T00:00:00Z", statusSuccess), // launched ahead of its NET
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{}, mongoDB)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := func(items any) []string {
+		ids := []string{}
+		for _, l := range items.([]models.LL2LaunchNormal) {
+			ids = append(ids, l.ID)
+		}
+		return ids
+	}
+
+	items, err := s.listLaunchesAround(context.Background(), now, true, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stale-tbd", "next", "later"}, ids(items))
+
+	items, err = s.listLaunchesAround(context.Background(), now, false, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"early-success", "failed", "sputnik"}, ids(items))
+}
+
+func TestGetLaunchByIDOrSlug(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	_, err := mongoDB.Collection(LL2COLLECTION).InsertOne(context.Background(), map[string]any{
+		"id":   "e3df2ecd-c239-472f-95e4-2b89b4f75800",
+		"slug": "falcon-9-block-5-starlink-group-6-1",
+		"name": "Falcon 9 Block 5 | Starlink Group 6-1",
+		"updates": []any{
+			map[string]any{"id": 1, "comment": "Launch confirmed"},
+		},
+		"vid_urls": []any{
+			map[string]any{"url": "https://www.youtube.com/watch?v=1"},
+		},
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{}, mongoDB)
+	for _, id := range []string{"e3df2ecd-c239-472f-95e4-2b89b4f75800", "falcon-9-block-5-starlink-group-6-1"} {
+		item, err := s.Get(context.Background(), "launches", id)
+		assert.NoError(t, err)
+		launch := item.(models.LL2LaunchDetailed)
+		assert.Equal(t, "e3df2ecd-c239-472f-95e4-2b89b4f75800", launch.ID)
+		assert.Len(t, launch.Updates, 1)
+		assert.Len(t, launch.VidURLs, 1)
+	}
+
+	_, err = s.Get(context.Background(), "launches", "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Get(context.Background(), "pads", "1")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,243 @@
+package service
+
+import "github.com/vamosdalian/launchdate-backend/internal/models"
+
+const LL2COLLECTION = "ll2_launch"
+
+// resources declares every LL2 endpoint that is synced,
+// adding an entry here is all that is needed to sync, list and route a new endpoint
+var resources = []*Resource{
+	{
+		Name:             "launches",
+		Endpoint:         "launches",
+		Collection:       LL2COLLECTION,
+		IDField:          "id",
+		SlugField:        "slug",
+		SortField:        "net",
+		IncrementalField: "last_updated",
+		HistoryFields:    []string{"net", "window_start", "window_end", "status"},
+		SlipField:        "net",
+		Filters: map[string]Filter{
+			"lsp":          {Field: "launch_service_provider"},
+			"rocket":       {Field: "rocket.configuration"},
+			"family":       {Field: "rocket.configuration.families"},
+			"pad":          {Field: "pad"},
+			"location":     {Field: "pad.location"},
+			"country":      {Field: "pad.country", Name: "alpha3_code"},
+			"status":       {Field: "status", Name: "abbrev"},
+			"orbit":        {Field: "mission.orbit", Name: "abbrev"},
+			"program":      {Field: "program"},
+			"mission_type": {Field: "mission.type", Kind: FilterText},
+			"net_after":    {Field: "net", Kind: FilterAfter},
+			"net_before":   {Field: "net", Kind: FilterBefore},
+			// only launches synced in detailed mode have their crew
+			"crewed": {Field: "rocket.spacecraft_stage.launch_crew", Kind: FilterNonEmpty},
+		},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2LaunchDetailed],
+			ModeNormal:   decodeAs[models.LL2LaunchNormal],
+			ModeList:     decodeAs[models.LL2LaunchBasic],
+		},
+		list:   listAs[models.LL2LaunchNormal],
+		detail: detailAs[models.LL2LaunchDetailed],
+	},
+	{
+		Name:       "agencies",
+		Endpoint:   "agencies",
+		Collection: "ll2_agency",
+		IDField:    "id",
+		SortField:  "id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2AgencyDetailed],
+			ModeNormal:   decodeAs[models.LL2AgencyNormal],
+			ModeList:     decodeAs[models.LL2AgencyMini],
+		},
+		prepare: resolveAgencyLists,
+		list:    listAs[models.LL2AgencyDetailed],
+		detail:  detailAs[models.LL2AgencyDetailed],
+	},
+	{
+		Name:       "launchers",
+		Endpoint:   "launcher_configurations",
+		Collection: "ll2_launcher",
+		IDField:    "id",
+		SortField:  "id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2LauncherConfigDetailed],
+			ModeNormal:   decodeAs[models.LL2LauncherConfigNormal],
+			ModeList:     decodeAs[models.LL2LauncherConfigList],
+		},
+		list:   listAs[models.LL2LauncherConfigNormal],
+		detail: detailAs[models.LL2LauncherConfigDetailed],
+	},
+	{
+		Name:       "launcher-families",
+		Endpoint:   "launcher_configuration_families",
+		Collection: "ll2_launcher_family",
+		IDField:    "id",
+		SortField:  "id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2LauncherConfigFamilyDetailed],
+			ModeNormal:   decodeAs[models.LL2LauncherConfigFamilyNormal],
+			ModeList:     decodeAs[models.LL2LauncherConfigFamilyMini],
+		},
+		list:   listAs[models.LL2LauncherConfigFamilyDetailed],
+		detail: detailAs[models.LL2LauncherConfigFamilyDetailed],
+	},
+	{
+		Name:       "locations",
+		Endpoint:   "locations",
+		Collection: "ll2_location",
+		IDField:    "id",
+		SortField:  "id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2LocationSerializerWithPads],
+		},
+		list:   listAs[models.LL2LocationSerializerWithPads],
+		detail: detailAs[models.LL2LocationSerializerWithPads],
+	},
+	{
+		Name:       "pads",
+		Endpoint:   "pads",
+		Collection: "ll2_pad",
+		IDField:    "id",
+		SortField:  "id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2Pad],
+		},
+		list:   listAs[models.LL2Pad],
+		detail: detailAs[models.LL2Pad],
+	},
+	{
+		Name:       "astronauts",
+		Endpoint:   "astronauts",
+		Collection: "ll2_astronaut",
+		IDField:    "id",
+		SortField:  "id",
+		Filters:    map[string]Filter{"status": {Field: "status"}, "agency": {Field: "agency"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2AstronautDetailed],
+			ModeNormal:   decodeAs[models.LL2AstronautNormal],
+		},
+		list:   listAs[models.LL2AstronautDetailed],
+		detail: detailAs[models.LL2AstronautDetailed],
+	},
+	{
+		Name:       "spacecraft-configurations",
+		Endpoint:   "spacecraft_configurations",
+		Collection: "ll2_spacecraft_config",
+		IDField:    "id",
+		SortField:  "id",
+		Filters:    map[string]Filter{"agency": {Field: "agency"}, "type": {Field: "type"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2SpacecraftConfigDetailed],
+			ModeNormal:   decodeAs[models.LL2SpacecraftConfigNormal],
+			ModeList:     decodeAs[models.LL2SpacecraftConfigMini],
+		},
+		list:   listAs[models.LL2SpacecraftConfigDetailed],
+		detail: detailAs[models.LL2SpacecraftConfigDetailed],
+	},
+	{
+		Name:       "spacecraft",
+		Endpoint:   "spacecraft",
+		Collection: "ll2_spacecraft",
+		IDField:    "id",
+		SortField:  "id",
+		Filters:    map[string]Filter{"status": {Field: "status"}, "config": {Field: "spacecraft_config"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2SpacecraftDetailed],
+			ModeNormal:   decodeAs[models.LL2SpacecraftNormal],
+		},
+		list:   listAs[models.LL2SpacecraftDetailed],
+		detail: detailAs[models.LL2SpacecraftDetailed],
+	},
+	{
+		Name:       "spacecraft-flights",
+		Endpoint:   "spacecraft/flights",
+		Collection: "ll2_spacecraft_flight",
+		IDField:    "id",
+		SortField:  "launch.net",
+		Filters:    map[string]Filter{"spacecraft": {Field: "spacecraft"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2SpacecraftFlightDetailed],
+		},
+		list:   listAs[models.LL2SpacecraftFlightDetailed],
+		detail: detailAs[models.LL2SpacecraftFlightDetailed],
+	},
+	{
+		Name:       "space-stations",
+		Endpoint:   "space_stations",
+		Collection: "ll2_space_station",
+		IDField:    "id",
+		SortField:  "id",
+		Filters:    map[string]Filter{"status": {Field: "status"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2SpaceStationDetailed],
+			ModeList:     decodeAs[models.LL2SpaceStationMini],
+		},
+		list:   listAs[models.LL2SpaceStationDetailed],
+		detail: detailAs[models.LL2SpaceStationDetailed],
+	},
+	{
+		Name:       "expeditions",
+		Endpoint:   "expeditions",
+		Collection: "ll2_expedition",
+		IDField:    "id",
+		SortField:  "start",
+		Filters:    map[string]Filter{"station": {Field: "spacestation"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2ExpeditionDetailed],
+			ModeList:     decodeAs[models.LL2ExpeditionMini],
+		},
+		list:   listAs[models.LL2ExpeditionDetailed],
+		detail: detailAs[models.LL2ExpeditionDetailed],
+	},
+	{
+		Name:       "docking-events",
+		Endpoint:   "docking_events",
+		Collection: "ll2_docking_event",
+		IDField:    "id",
+		SortField:  "docking",
+		Filters:    map[string]Filter{"station": {Field: "space_station_target"}},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2DockingEventDetailed],
+		},
+		list:   listAs[models.LL2DockingEventDetailed],
+		detail: detailAs[models.LL2DockingEventDetailed],
+	},
+	{
+		Name:             "events",
+		Endpoint:         "events",
+		Collection:       "ll2_event",
+		IDField:          "id",
+		SlugField:        "slug",
+		SortField:        "date",
+		IncrementalField: "last_updated",
+		Filters: map[string]Filter{
+			"type":        {Field: "type"},
+			"launch":      {Field: "launches.id", Kind: FilterEqual},
+			"date_after":  {Field: "date", Kind: FilterAfter},
+			"date_before": {Field: "date", Kind: FilterBefore},
+		},
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2EventDetailed],
+		},
+		list:   listAs[models.LL2EventDetailed],
+		detail: detailAs[models.LL2EventDetailed],
+	},
+	{
+		Name:          "programs",
+		Endpoint:      "programs",
+		Collection:    "ll2_program",
+		IDField:       "id",
+		SortField:     "id",
+		Filters:       map[string]Filter{"type": {Field: "type"}, "agency": {Field: "agencies"}},
+		LaunchesField: "program.id",
+		decode: map[string]decodeFunc{
+			ModeDetailed: decodeAs[models.LL2ProgramDetailed],
+			ModeNormal:   decodeAs[models.LL2ProgramNormal],
+		},
+		list:   listAs[models.LL2ProgramDetailed],
+		detail: detailAs[models.LL2ProgramDetailed],
+	},
+}
@@ -2,236 +2,154 @@ package service
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vamosdalian/launchdate-backend/internal/config"
 	"github.com/vamosdalian/launchdate-backend/internal/db"
-	"github.com/vamosdalian/launchdate-backend/internal/models"
-	"github.com/vamosdalian/launchdate-backend/internal/util"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const LL2COLLECTION = "ll2_launch"
-
 type LL2Service struct {
-	mongoClient        *db.MongoDB
+	mongoClient  *db.MongoDB
+	jobs         *JobManager
+	client       *http.Client
+	retry        RetryPolicy
+	adaptiveRate bool
+	token        string
+	// tierInterval paces syncs by the request budget of token as no interval is configured
+	tierInterval bool
+	requests     requestConfig
+	// transportErr is reported by ValidateSettings if the configured transport is unknown
+	transportErr       error
 	LL2URLPrefix       string
 	LL2RequestInterval int
 }
 
+// defaultRequestInterval is the interval in seconds between anonymous LL2 requests
+const defaultRequestInterval = 5
+
 func NewLL2Service(conf *config.Config, db *db.MongoDB) *LL2Service {
+	interval := conf.LL2RequestInterval
+	if interval <= 0 {
+		interval = defaultRequestInterval
+	}
+	transport, transportErr := newTransport(conf.LL2Transport, conf.LL2FixtureDir)
 	return &LL2Service{
-		mongoClient:        db,
+		mongoClient: db,
+		jobs:        NewJobManager(),
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		transportErr: transportErr,
+		retry: RetryPolicy{
+			MaxRetries: conf.LL2MaxRetries,
+			BaseDelay:  conf.LL2RetryBaseDelay,
+			MaxDelay:   conf.LL2RetryMaxDelay,
+		},
+		adaptiveRate:       conf.LL2AdaptiveRate,
+		token:              conf.LL2APIToken,
+		tierInterval:       conf.LL2APIToken != "" && conf.LL2RequestInterval <= 0,
+		requests:           newRequestConfig(conf),
 		LL2URLPrefix:       conf.LL2URLPrefix,
-		LL2RequestInterval: conf.LL2RequestInterval,
+		LL2RequestInterval: interval,
 	}
 }
 
-// if async is true, function runs in background
-// otherwise, it runs synchronously
-func (s *LL2Service) UpdateLaunches(async bool) error {
-	if async {
-		go func() {
-			err := s.updateLaunchesAsync()
-			if err != nil {
-				logrus.Errorf("%s", err)
-			}
-		}()
-		return nil
+// ValidateSettings checks the transport and the per resource request settings of the service
+func (s *LL2Service) ValidateSettings() error {
+	if s.transportErr != nil {
+		return s.transportErr
 	}
-	return s.updateLaunchesAsync()
+	return s.requests.validate()
 }
 
-func (s *LL2Service) updateLaunchesAsync() error {
-	count := 1
-	offset := 0
-	rl := util.NewRateLimit(time.Duration(s.LL2RequestInterval) * time.Second)
-	logrus.Info("Starting LL2 launches update...")
-	for {
-		if offset >= count {
-			break
-		}
+// Jobs returns the manager tracking sync jobs of this service
+func (s *LL2Service) Jobs() *JobManager {
+	return s.jobs
+}
 
-		rl.Wait()
-		launches, err := s.LoadLaunches(10, offset)
-		if err != nil {
-			return err
-		}
-		count = launches.Count
-		logrus.Infof("Fetched %d/%d launches from LL2", offset+len(launches.Results), count)
+// fetchFromAPI returns the raw body of a single LL2 page
+func (s *LL2Service) fetchFromAPI(ctx context.Context, version, endpoint, mode string, limit, offset int, query url.Values) ([]byte, error) {
+	reqURL := fmt.Sprintf("%s/%s/%s?limit=%d&offset=%d&mode=%s", s.LL2URLPrefix, version, endpoint, limit, offset, mode)
+	if len(query) > 0 {
+		reqURL += "&" + query.Encode()
+	}
+	return s.fetchURL(ctx, reqURL)
+}
 
-		for _, launch := range launches.Results {
-			filter := map[string]any{
-				"id": launch.ID,
-			}
-			update := map[string]any{
-				"$set": launch,
-			}
-			opts := options.Update().SetUpsert(true)
-			_, err := s.mongoClient.Collection(LL2COLLECTION).UpdateOne(context.Background(), filter, update, opts)
-			if err != nil {
-				return err
+// fetchURL returns the body of an LL2 request,
+// retrying transient failures according to the retry policy of the service until ctx is done
+func (s *LL2Service) fetchURL(ctx context.Context, reqURL string) ([]byte, error) {
+	var err error
+	for retry := 0; ; retry++ {
+		if retry > 0 {
+			delay := s.retry.retryDelay(retry, err)
+			logrus.Warnf("LL2 request failed: %s, retry %d/%d in %s", err, retry, s.retry.MaxRetries, delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
 			}
 		}
-		offset += len(launches.Results)
+		var body []byte
+		body, err = s.get(ctx, reqURL)
+		if err == nil || !IsTemporary(err) || retry >= s.retry.MaxRetries {
+			return body, err
+		}
 	}
-	return nil
-}
-
-func (s *LL2Service) LoadLaunches(limit, offset int) (*models.LL2Response, error) {
-	var launches *models.LL2Response
-	err := s.LoadDataFromAPI("launches", limit, offset, launches)
-
-	return launches, err
 }
 
-func (s *LL2Service) GetLaunchesFromDB(limit, offset int) ([]models.LL2LaunchNormal, error) {
-	collection := s.mongoClient.Collection(LL2COLLECTION)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	findOptions := options.Find()
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSkip(int64(offset))
-	findOptions.SetSort(map[string]int{"net": 1}) // Sort by net ascending
-
-	cursor, err := collection.Find(ctx, map[string]any{}, findOptions)
+// get performs a single LL2 request
+func (s *LL2Service) get(ctx context.Context, reqURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var launches []models.LL2LaunchNormal
-	for cursor.Next(ctx) {
-		var launch models.LL2LaunchNormal
-		if err := cursor.Decode(&launch); err != nil {
-			return nil, err
-		}
-		launches = append(launches, launch)
+		return nil, errors.New(s.redact(err.Error()))
 	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, err
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
 	}
-
-	return launches, nil
-}
-
-func (s *LL2Service) LoadAngecyFromAPI(limit, offset int) (*models.LL2AngecyResponse, error) {
-	var launches *models.LL2AngecyResponse
-	err := s.LoadDataFromAPI("agencies", limit, offset, &launches)
-	return launches, err
-}
-
-func (s *LL2Service) LoadDataFromAPI(endpoint string, limit, offset int, payload any) error {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	url := fmt.Sprintf("%s/2.3.0/%s?limit=%d&offset=%d&mode=detailed", s.LL2URLPrefix, endpoint, limit, offset)
-	resp, err := client.Get(url)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return err
+		// a canceled request is not worth retrying
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = s.redact(urlErr.URL)
+		}
+		return nil, &transportError{err: err}
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("LL2 API returned status code %d, url:%s", resp.StatusCode, url)
+		apiErr := &APIError{StatusCode: resp.StatusCode, URL: s.redact(reqURL)}
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		return nil, apiErr
 	}
-	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(body, &payload)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (s *LL2Service) GetAngecyFromDB(limit, offset int) ([]models.LL2AgencyDetailed, error) {
-	collection := s.mongoClient.Collection("ll2_agency")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	findOptions := options.Find()
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSkip(int64(offset))
-	findOptions.SetSort(map[string]int{"id": 1}) // Sort by id ascending
-
-	cursor, err := collection.Find(ctx, map[string]any{}, findOptions)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var agencies []models.LL2AgencyDetailed
-	for cursor.Next(ctx) {
-		var agency models.LL2AgencyDetailed
-		if err := cursor.Decode(&agency); err != nil {
-			return nil, err
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		agencies = append(agencies, agency)
+		return nil, &transportError{err: err}
 	}
-
-	if err := cursor.Err(); err != nil {
-		return nil, err
-	}
-
-	return agencies, nil
+	return body, nil
 }
 
-func (s *LL2Service) UpdateAngecy(async bool) error {
-	if async {
-		go func() {
-			err := s.updateAngecyAsync()
-			if err != nil {
-				logrus.Errorf("%s", err)
-			}
-		}()
-		return nil
-	}
-	return s.updateAngecyAsync()
-}
-
-func (s *LL2Service) updateAngecyAsync() error {
-	count := 10
-	offset := 0
-	rl := util.NewRateLimit(time.Duration(s.LL2RequestInterval) * time.Second)
-	logrus.Info("Starting LL2 angecy update...")
-	for {
-		if offset >= count {
-			break
-		}
-
-		rl.Wait()
-		agencies, err := s.LoadAngecyFromAPI(10, offset)
-		if err != nil {
-			return err
-		}
-		count = agencies.Count
-		logrus.Infof("Fetched %d/%d angecies from LL2", offset+len(agencies.Results), count)
-
-		for _, agency := range agencies.Results {
-			filter := map[string]any{
-				"id": agency.ID,
-			}
-			update := map[string]any{
-				"$set": agency,
-			}
-			opts := options.Update().SetUpsert(true)
-			_, err := s.mongoClient.Collection("ll2_agency").UpdateOne(context.Background(), filter, update, opts)
-			if err != nil {
-				return err
-			}
-		}
-		offset += len(agencies.Results)
+// redact replaces the API token in s, e.g. a URL or an error message, so it can be logged
+func (s *LL2Service) redact(str string) string {
+	if s.token == "" {
+		return str
 	}
-	return nil
+	return strings.ReplaceAll(str, s.token, "[REDACTED]")
 }
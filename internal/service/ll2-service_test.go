@@ -4,14 +4,17 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
 	"github.com/vamosdalian/launchdate-backend/internal/config"
 	"github.com/vamosdalian/launchdate-backend/internal/db"
+	"github.com/vamosdalian/launchdate-backend/internal/ll2mock"
 	"github.com/vamosdalian/launchdate-backend/internal/models"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -65,18 +68,19 @@ func TestLoadLaunches(t *testing.T) {
 	// Use server.URL as the base URL for the service
 	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL}, nil)
 
-	launches, err := s.LoadLaunches(1, 0)
+	r, _ := LookupResource("launches")
+	launches, err := s.loadPage(context.Background(), r, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	if len(launches.Results) != 1 {
-		t.Fatalf("Expected 1 launch, got %d", len(launches.Results))
+	if len(launches.Docs) != 1 {
+		t.Fatalf("Expected 1 launch, got %d", len(launches.Docs))
 	}
 
 	expectedID := "eed1132a-d5aa-4c9c-bc38-c8ccb98829b6"
-	if launches.Results[0].ID != expectedID {
-		t.Fatalf("Expected launch ID %s, got %s", expectedID, launches.Results[0].ID)
+	if launches.Docs[0]["id"] != expectedID {
+		t.Fatalf("Expected launch ID %s, got %v", expectedID, launches.Docs[0]["id"])
 	}
 }
 
@@ -97,14 +101,15 @@ func TestLoadAgency(t *testing.T) {
 	// Use server.URL as the base URL for the service
 	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL}, nil)
 
-	agency, err := s.LoadAngecyFromAPI(1, 0)
+	r, _ := LookupResource("agencies")
+	agency, err := s.loadPage(context.Background(), r, 1, 0, nil)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	expectedID := 225
-	if agency.Results[0].ID != expectedID {
-		t.Fatalf("Expected agency ID %d, got %d", expectedID, agency.Results[0].ID)
+	expectedID := int32(225)
+	if agency.Docs[0]["id"] != expectedID {
+		t.Fatalf("Expected agency ID %d, got %v", expectedID, agency.Docs[0]["id"])
 	}
 }
 
@@ -123,8 +128,16 @@ func TestUpdateLaunches(t *testing.T) {
 
 	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
 
-	err := s.UpdateLaunches(false)
+	job, err := s.Update(context.Background(), "launches", false, SyncOptions{Full: true})
 	assert.NoError(t, err)
+	assert.Equal(t, 1, job.Pages)
+	assert.Equal(t, int64(1), job.Upserted)
+
+	// writing the same page again matches the stored document instead of inserting it
+	job, err = s.Update(context.Background(), "launches", false, SyncOptions{Full: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), job.Matched)
+	assert.Equal(t, int64(0), job.Upserted)
 
 	var launch models.LL2LaunchNormal
 	err = mongoDB.Collection(LL2COLLECTION).FindOne(context.Background(), map[string]any{"id": "eed1132a-d5aa-4c9c-bc38-c8ccb98829b6"}).Decode(&launch)
@@ -147,7 +160,7 @@ func TestUpdateAngecy(t *testing.T) {
 
 	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
 
-	err := s.UpdateAngecy(false)
+	_, err := s.Update(context.Background(), "agencies", false, SyncOptions{})
 	assert.NoError(t, err)
 
 	var agency models.LL2AgencyDetailed
@@ -155,3 +168,408 @@ func TestUpdateAngecy(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 225, agency.ID)
 }
+
+func TestUpdateLaunchesIncremental(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "sample.json"))
+		if err != nil {
+			t.Fatalf("Failed to read sample.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+
+	// nothing stored yet, so the first run crawls everything
+	_, err := s.Update(context.Background(), "launches", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, queries[0].Get("last_updated__gte"))
+
+	_, err = s.Update(context.Background(), "launches", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-10-30T13:39:57Z", queries[1].Get("last_updated__gte"))
+	assert.Equal(t, "last_updated", queries[1].Get("ordering"))
+
+	// a full resync ignores what is stored
+	_, err = s.Update(context.Background(), "launches", false, SyncOptions{Full: true})
+	assert.NoError(t, err)
+	assert.Empty(t, queries[2].Get("last_updated__gte"))
+}
+
+func TestUpdateLaunchesIncrementalKeysetPaging(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	_, err := mongoDB.Collection(LL2COLLECTION).InsertOne(context.Background(), map[string]any{
+		"id": "old", "last_updated": "2024-01-01T00:00:00Z",
+	})
+	assert.NoError(t, err)
+
+	launch := func(id, lastUpdated string) map[string]any {
+		return map[string]any{"id": id, "last_updated": lastUpdated}
+	}
+	launches := []map[string]any{
+		launch("old", "2024-01-01T00:00:00Z"),
+		launch("a", "2024-01-02T00:00:00Z"),
+		launch("b", "2024-01-03T00:00:00Z"),
+		launch("c", "2024-01-04T00:00:00Z"),
+	}
+	mock := ll2mock.New("2.3.0", map[string][]map[string]any{"launches": launches}, ll2mock.Faults{})
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if strings.Contains(req.URL.Path, "/launches") {
+			queries = append(queries, req.URL.Query())
+			// a is updated after the first page, moving it behind b and c
+			if len(queries) == 2 {
+				launches[1]["last_updated"] = "2024-01-05T00:00:00Z"
+			}
+		}
+		mock.ServeHTTP(rw, req)
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1, LL2PageSize: 2}, mongoDB)
+	_, err = s.Update(context.Background(), "launches", false, SyncOptions{})
+	assert.NoError(t, err)
+
+	since := []string{}
+	for _, q := range queries {
+		assert.Equal(t, "0", q.Get("offset"))
+		since = append(since, q.Get("last_updated__gte"))
+	}
+	assert.Equal(t, []string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z", "2024-01-04T00:00:00Z"}, since)
+
+	for _, id := range []string{"a", "b", "c"} {
+		n, err := mongoDB.Collection(LL2COLLECTION).CountDocuments(context.Background(), map[string]any{"id": id})
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, n, id)
+	}
+}
+
+func TestUpdateAngecyResumesFromCheckpoint(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		offsets = append(offsets, req.URL.Query().Get("offset"))
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+
+	cp := &SyncCheckpoint{Resource: "agencies"}
+	assert.NoError(t, s.commitPage(context.Background(), cp, 5))
+
+	_, err := s.Update(context.Background(), "agencies", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5"}, offsets)
+
+	// a completed sync clears its checkpoint
+	checkpoints, err := s.GetCheckpoints(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, checkpoints)
+}
+
+func TestUpdateAngecyTombstonesRemoved(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	// an agency no longer returned by LL2
+	_, err := mongoDB.Collection("ll2_agency").InsertOne(context.Background(), map[string]any{"id": 1, "name": "Gone"})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+
+	job, err := s.Update(context.Background(), "agencies", false, SyncOptions{Full: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), job.Removed)
+
+	var gone models.LL2AgencyDetailed
+	err = mongoDB.Collection("ll2_agency").FindOne(context.Background(), map[string]any{"id": 1}).Decode(&gone)
+	assert.NoError(t, err)
+	assert.NotNil(t, gone.RemovedAt)
+
+	items, err := s.List(context.Background(), "agencies", ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	for _, agency := range items.([]models.LL2AgencyDetailed) {
+		assert.NotEqual(t, 1, agency.ID)
+	}
+
+	items, err = s.List(context.Background(), "agencies", ListOptions{Limit: 10, IncludeRemoved: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, items.([]models.LL2AgencyDetailed)[0].ID)
+}
+
+func TestUpdateAngecyKeepsRecordsAfterEmptyPage(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	agencies := []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}}
+	mock := ll2mock.New("2.3.0", map[string][]map[string]any{"agencies": agencies}, ll2mock.Faults{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// LL2 glitches and answers the second page with no results
+		if req.URL.Query().Get("offset") == "2" {
+			rw.Write([]byte(`{"count": 3, "results": []}`))
+			return
+		}
+		mock.ServeHTTP(rw, req)
+	}))
+	defer server.Close()
+
+	_, err := mongoDB.Collection("ll2_agency").InsertMany(context.Background(), []any{
+		map[string]any{"id": 3, "name": "c"},
+		map[string]any{"id": 4, "name": "d"},
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1, LL2PageSize: 2}, mongoDB)
+	job, err := s.Update(context.Background(), "agencies", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Zero(t, job.Removed)
+
+	n, err := mongoDB.Collection("ll2_agency").CountDocuments(context.Background(), map[string]any{removedAtField: map[string]any{"$exists": true}})
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestUpdateAngecyKeepsRecordsShiftedBetweenPages(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	agencies := []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}, {"id": 4, "name": "d"}}
+	mock := ll2mock.New("2.3.0", map[string][]map[string]any{"agencies": agencies}, ll2mock.Faults{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// c moves onto the first page after it was fetched, so the run never sees it
+		if req.URL.Query().Get("offset") == "2" {
+			agencies[0], agencies[1], agencies[2] = agencies[2], agencies[0], agencies[1]
+		}
+		mock.ServeHTTP(rw, req)
+	}))
+	defer server.Close()
+
+	_, err := mongoDB.Collection("ll2_agency").InsertOne(context.Background(), map[string]any{"id": 3, "name": "c"})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1, LL2PageSize: 2}, mongoDB)
+	job, err := s.Update(context.Background(), "agencies", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Zero(t, job.Removed)
+
+	var c models.LL2AgencyDetailed
+	err = mongoDB.Collection("ll2_agency").FindOne(context.Background(), map[string]any{"id": 3}).Decode(&c)
+	assert.NoError(t, err)
+	assert.Nil(t, c.RemovedAt)
+}
+
+func TestUpdateLaunchesRecordsSlips(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "sample.json"))
+		if err != nil {
+			t.Fatalf("Failed to read sample.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	// the launch as stored before it slipped by a day
+	id := "eed1132a-d5aa-4c9c-bc38-c8ccb98829b6"
+	_, err := mongoDB.Collection(LL2COLLECTION).InsertOne(context.Background(), map[string]any{
+		"id":           id,
+		"net":          "2024-10-29T12:07:00Z",
+		"window_start": "2024-10-30T11:07:00Z",
+		"window_end":   "2024-10-30T12:09:00Z",
+		"status":       map[string]any{"id": 3, "name": "Launch Successful", "abbrev": "Success", "description": "The launch vehicle successfully inserted its payload(s) into the target orbit(s)."},
+		"slip_count":   2,
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+
+	_, err = s.Update(context.Background(), "launches", false, SyncOptions{Full: true})
+	assert.NoError(t, err)
+
+	entries, err := s.History(context.Background(), "launches", id, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "net", entries[0].Field)
+		assert.Equal(t, "2024-10-29T12:07:00Z", entries[0].Old)
+		assert.Equal(t, "2024-10-30T12:07:00Z", entries[0].New)
+	}
+
+	var launch models.LL2LaunchNormal
+	err = mongoDB.Collection(LL2COLLECTION).FindOne(context.Background(), map[string]any{"id": id}).Decode(&launch)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, launch.SlipCount)
+
+	// an unchanged launch records nothing
+	_, err = s.Update(context.Background(), "launches", false, SyncOptions{Full: true})
+	assert.NoError(t, err)
+	entries, err = s.History(context.Background(), "launches", id, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestListProgramLaunches(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	artemis := map[string]any{"id": 17, "name": "Artemis"}
+	crew := map[string]any{"id": 18, "name": "Commercial Crew Program"}
+	_, err := mongoDB.Collection(LL2COLLECTION).InsertMany(context.Background(), []any{
+		map[string]any{"id": "a", "name": "SLS Block 1 | Artemis II", "net": "2026-04-01T00:00:00Z", "program": []any{artemis}},
+		map[string]any{"id": "b", "name": "Falcon 9 Block 5 | Crew-8", "net": "2024-03-04T03:53:38Z", "program": []any{crew}},
+		map[string]any{"id": "c", "name": "SLS Block 1 | Artemis I", "net": "2022-11-16T06:47:44Z", "program": []any{artemis, crew}},
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{}, mongoDB)
+	items, err := s.ListLaunches(context.Background(), "programs", "17", ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	launches := items.([]models.LL2LaunchNormal)
+	if assert.Len(t, launches, 2) {
+		assert.Equal(t, "c", launches[0].ID)
+		assert.Equal(t, "a", launches[1].ID)
+	}
+}
+
+func TestUpdateAngecyResolvesLauncherList(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	// agencies.json returns an empty launcher_list, it is resolved from the stored launchers
+	_, err := mongoDB.Collection("ll2_launcher").InsertMany(context.Background(), []any{
+		map[string]any{"id": 164, "name": "Falcon 9", "manufacturer": map[string]any{"id": 225}},
+		map[string]any{"id": 1, "name": "Other", "manufacturer": map[string]any{"id": 1}},
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+
+	_, err = s.Update(context.Background(), "agencies", false, SyncOptions{})
+	assert.NoError(t, err)
+
+	var agency models.LL2AgencyDetailed
+	err = mongoDB.Collection("ll2_agency").FindOne(context.Background(), map[string]any{"id": 225}).Decode(&agency)
+	assert.NoError(t, err)
+	if assert.Len(t, agency.LauncherList, 1) {
+		assert.Equal(t, 164, agency.LauncherList[0].ID)
+	}
+	assert.Empty(t, agency.SpacecraftList)
+}
+
+func TestUpdateAngecyFromReplay(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	s := NewLL2Service(&config.Config{
+		LL2URLPrefix:       "http://ll2.invalid",
+		LL2RequestInterval: 1,
+		LL2PageSize:        1,
+		LL2Transport:       TransportReplay,
+		LL2FixtureDir:      filepath.Join("testdata", "ll2"),
+	}, mongoDB)
+
+	job, err := s.Update(context.Background(), "agencies", false, SyncOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, job.Pages)
+	assert.Equal(t, int64(2), job.Upserted)
+}
+
+func TestUpdateLaunchesIncrementalRecordThenReplay(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	launches := []map[string]any{
+		{"id": "old", "last_updated": "2024-01-01T00:00:00Z"},
+		{"id": "a", "last_updated": "2024-01-02T00:00:00Z"},
+		{"id": "b", "last_updated": "2024-01-03T00:00:00Z"},
+		{"id": "c", "last_updated": "2024-01-04T00:00:00Z"},
+	}
+	server := httptest.NewServer(ll2mock.New("2.3.0", map[string][]map[string]any{"launches": launches}, ll2mock.Faults{}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	collection := mongoDB.Collection(LL2COLLECTION)
+	update := func(transport string) Job {
+		// every run starts from the same stored launch, so it pages through the same filters
+		_, err := collection.DeleteMany(context.Background(), map[string]any{})
+		assert.NoError(t, err)
+		_, err = collection.InsertOne(context.Background(), launches[0])
+		assert.NoError(t, err)
+
+		s := NewLL2Service(&config.Config{
+			LL2URLPrefix:       server.URL,
+			LL2RequestInterval: 1,
+			LL2PageSize:        2,
+			LL2Transport:       transport,
+			LL2FixtureDir:      dir,
+		}, mongoDB)
+		job, err := s.Update(context.Background(), "launches", false, SyncOptions{})
+		assert.NoError(t, err)
+		return job
+	}
+
+	recorded := update(TransportRecord)
+	server.Close()
+	replayed := update(TransportReplay)
+	assert.Equal(t, recorded.Pages, replayed.Pages)
+	assert.Greater(t, replayed.Pages, 1)
+
+	n, err := collection.CountDocuments(context.Background(), map[string]any{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, n)
+}
+
+func TestUpdateEveryResourceFromMock(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	fixtures, err := ll2mock.LoadFixtures(filepath.Join("..", "..", "cmd", "ll2mock", "fixtures"))
+	assert.NoError(t, err)
+	server := httptest.NewServer(ll2mock.New("2.3.0", fixtures, ll2mock.Faults{}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2RequestInterval: 1}, mongoDB)
+	for _, r := range Resources() {
+		job, err := s.Update(context.Background(), r.Name, false, SyncOptions{})
+		assert.NoError(t, err, r.Name)
+		assert.Equal(t, JobSucceeded, job.Status, r.Name)
+
+		n, err := mongoDB.Collection(r.Collection).CountDocuments(context.Background(), map[string]any{})
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(fixtures[r.Endpoint]), n, r.Name)
+	}
+}
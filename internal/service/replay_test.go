@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+func TestFixturePath(t *testing.T) {
+	u, _ := url.Parse("https://ll.thespacedevs.com/2.3.0/launches?limit=10&offset=20&mode=detailed")
+	assert.Equal(t, filepath.Join("fixtures", "2.3.0", "launches", "limit-10_offset-20.json"), fixturePath("fixtures", u))
+
+	// filters are part of the key, regardless of their order
+	u, _ = url.Parse("https://ll.thespacedevs.com/2.3.0/launches?limit=10&offset=0&mode=detailed&ordering=last_updated&last_updated__gte=2024-01-01T00:00:00Z")
+	first := fixturePath("fixtures", u)
+	assert.Regexp(t, `limit-10_offset-0_filter-[0-9a-f]{8}\.json$`, first)
+	u, _ = url.Parse("https://ll.thespacedevs.com/2.3.0/launches?last_updated__gte=2024-01-01T00:00:00Z&limit=10&offset=0&mode=normal&ordering=last_updated")
+	assert.Equal(t, first, fixturePath("fixtures", u))
+	u, _ = url.Parse("https://ll.thespacedevs.com/2.3.0/launches?limit=10&offset=0&mode=detailed&ordering=last_updated&last_updated__gte=2024-01-02T00:00:00Z")
+	assert.NotEqual(t, first, fixturePath("fixtures", u))
+
+	u, _ = url.Parse("https://ll.thespacedevs.com/2.3.0/api-throttle/")
+	assert.Equal(t, filepath.Join("fixtures", "2.3.0", "api-throttle", "index.json"), fixturePath("fixtures", u))
+}
+
+func TestReplayMultiplePages(t *testing.T) {
+	s := NewLL2Service(&config.Config{
+		LL2URLPrefix:  "http://ll2.invalid",
+		LL2Transport:  TransportReplay,
+		LL2FixtureDir: filepath.Join("testdata", "ll2"),
+	}, nil)
+	assert.NoError(t, s.ValidateSettings())
+	r, _ := LookupResource("agencies")
+
+	page, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Count)
+	assert.EqualValues(t, 225, page.Docs[0]["id"])
+
+	page, err = s.loadPage(context.Background(), r, 1, 1, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 226, page.Docs[0]["id"])
+
+	// a page that was not recorded is not found rather than fetched
+	_, err = s.loadPage(context.Background(), r, 1, 2, nil)
+	var apiErr *APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	}
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	r, _ := LookupResource("agencies")
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2Transport: TransportRecord, LL2FixtureDir: dir}, nil)
+	_, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, "2.3.0", "agencies", "limit-1_offset-0.json"))
+
+	server.Close()
+	s = NewLL2Service(&config.Config{LL2URLPrefix: server.URL, LL2Transport: TransportReplay, LL2FixtureDir: dir}, nil)
+	page, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 225, page.Docs[0]["id"])
+}
+
+func TestUnknownTransport(t *testing.T) {
+	s := NewLL2Service(&config.Config{LL2Transport: "cassette"}, nil)
+	assert.Error(t, s.ValidateSettings())
+}
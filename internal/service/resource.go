@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Resource declares an LL2 endpoint that is synced into a Mongo collection.
+// Every declared resource gets the same sync loop, list-from-DB query and HTTP routes.
+type Resource struct {
+	// Name identifies the resource in jobs, checkpoints, schedules and
+	// the HTTP routes /api/v1/ll2/<Name> and /api/v1/ll2/<Name>/update
+	Name string
+	// Endpoint is the LL2 API endpoint, e.g. "launcher_configurations"
+	Endpoint   string
+	Collection string
+	// IDField is the field documents are matched on when upserting
+	IDField string
+	// SlugField, if set, lets the detail endpoint also find documents by this field
+	SlugField string
+	// SortField is the field lists from DB are sorted ascending by
+	SortField string
+	// IncrementalField, if set, lets a sync fetch only records whose field is
+	// greater or equal to the newest stored value, e.g. "last_updated"
+	IncrementalField string
+	// HistoryFields, if set, records every change of these fields in <Collection>_history,
+	// served by /api/v1/ll2/<Name>/:id/history
+	HistoryFields []string
+	// SlipField is the history field whose changes are counted in slip_count
+	SlipField string
+	// Filters declares the query parameters the list endpoint can be filtered by
+	Filters map[string]Filter
+	// LaunchesField, if set, is the launch field linking launches to a record,
+	// served by /api/v1/ll2/<Name>/:id/launches
+	LaunchesField string
+
+	// decode parses an LL2 page requested in a mode into documents ready to be stored,
+	// every resource supports ModeDetailed
+	decode map[string]decodeFunc
+	// prepare, if set, completes decoded documents before they are written,
+	// e.g. by resolving relations against other collections
+	prepare func(ctx context.Context, s *LL2Service, docs []bson.M) error
+	// list decodes documents read from DB into the type returned by the list endpoint
+	list func(ctx context.Context, cursor *mongo.Cursor) (any, error)
+	// detail decodes a single document read from DB into the type returned by
+	// the detail endpoint /api/v1/ll2/<Name>/:id
+	detail func(res *mongo.SingleResult) (any, error)
+}
+
+// decodeFunc parses an LL2 page
+type decodeFunc func(body []byte) (*resourcePage, error)
+
+// resourcePage is a decoded LL2 page
+type resourcePage struct {
+	Count int
+	Next  string
+	Docs  []bson.M
+}
+
+// decodeAs decodes an LL2 page whose results are of type T
+func decodeAs[T any](body []byte) (*resourcePage, error) {
+	var payload struct {
+		Count   int    `json:"count"`
+		Next    string `json:"next"`
+		Results []T    `json:"results"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	page := &resourcePage{
+		Count: payload.Count,
+		Next:  payload.Next,
+		Docs:  make([]bson.M, 0, len(payload.Results)),
+	}
+	for _, result := range payload.Results {
+		doc, err := toBSON(result)
+		if err != nil {
+			return nil, err
+		}
+		page.Docs = append(page.Docs, doc)
+	}
+	return page, nil
+}
+
+// listAs decodes all documents of cursor as T
+func listAs[T any](ctx context.Context, cursor *mongo.Cursor) (any, error) {
+	items := []T{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// detailAs decodes a single document as T
+func detailAs[T any](res *mongo.SingleResult) (any, error) {
+	var item T
+	if err := res.Decode(&item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// toBSON converts v to a document using its bson tags
+func toBSON(v any) (bson.M, error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Resources returns all declared LL2 resources
+func Resources() []*Resource {
+	return resources
+}
+
+// LookupResource returns the declared resource with the given name
+func LookupResource(name string) (*Resource, bool) {
+	for _, r := range resources {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
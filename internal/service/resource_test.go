@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/ll2mock"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestResourcesAreComplete(t *testing.T) {
+	names := map[string]bool{}
+	collections := map[string]bool{}
+	for _, r := range Resources() {
+		assert.NotEmpty(t, r.Name)
+		assert.NotEmpty(t, r.Endpoint, r.Name)
+		assert.NotEmpty(t, r.Collection, r.Name)
+		assert.NotEmpty(t, r.IDField, r.Name)
+		assert.NotEmpty(t, r.SortField, r.Name)
+		assert.NotNil(t, r.decode[ModeDetailed], r.Name)
+		assert.NotNil(t, r.list, r.Name)
+		assert.NotNil(t, r.detail, r.Name)
+
+		assert.False(t, names[r.Name], "duplicate resource %s", r.Name)
+		assert.False(t, collections[r.Collection], "duplicate collection %s", r.Collection)
+		names[r.Name] = true
+		collections[r.Collection] = true
+
+		found, ok := LookupResource(r.Name)
+		assert.True(t, ok)
+		assert.Same(t, r, found)
+	}
+
+	_, ok := LookupResource("rockets")
+	assert.False(t, ok)
+}
+
+func TestDecodeAs(t *testing.T) {
+	body := []byte(`{"count": 2, "next": "https://ll.thespacedevs.com/2.3.0/pads/?limit=1&offset=1", "results": [{"id": 87, "name": "Launch Complex 39A", "location": {"id": 27}}]}`)
+	r, _ := LookupResource("pads")
+
+	page, err := r.decode[ModeDetailed](body)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Count)
+	assert.Len(t, page.Docs, 1)
+	assert.EqualValues(t, 87, page.Docs[0]["id"])
+	assert.Equal(t, "Launch Complex 39A", page.Docs[0]["name"])
+}
+
+func TestDecodeAstronauts(t *testing.T) {
+	body := []byte(`{"count": 1, "results": [{"id": 276, "name": "Sunita Williams", "status": {"id": 1, "name": "Active"},
+		"agency": {"id": 44, "name": "National Aeronautics and Space Administration", "abbrev": "NASA"},
+		"time_in_space": "P608DT19H1M", "age": null, "nationality": [{"id": 1, "alpha_3_code": "USA"}],
+		"flights_count": 3, "flights": [{"id": "a5ed2a8c-1bfe-42b3-a92f-70f1a1ca6c4a", "name": "Atlas V N22 | Starliner CFT", "net": "2024-06-05T14:52:15Z", "rocket": {"id": 1}}]}]}`)
+	r, _ := LookupResource("astronauts")
+
+	page, err := r.decode[ModeDetailed](body)
+	assert.NoError(t, err)
+	assert.Len(t, page.Docs, 1)
+	doc := page.Docs[0]
+	assert.Equal(t, "P608DT19H1M", doc["time_in_space"])
+	assert.Equal(t, "NASA", doc["agency"].(bson.M)["abbrev"])
+	assert.Equal(t, "USA", doc["nationality"].(bson.A)[0].(bson.M)["alpha3_code"])
+	flights := doc["flights"].(bson.A)
+	assert.Len(t, flights, 1)
+	assert.Equal(t, "2024-06-05T14:52:15Z", flights[0].(bson.M)["net"])
+	assert.NotContains(t, flights[0].(bson.M), "rocket")
+}
+
+func TestDecodeSpacecraftFlights(t *testing.T) {
+	body := []byte(`{"count": 1, "results": [{"id": 1250, "destination": "International Space Station",
+		"spacecraft": {"id": 254, "name": "Crew Dragon Endeavour", "serial_number": "C206", "spacecraft_config": {"id": 43, "name": "Dragon 2"}},
+		"launch": {"id": "e3df2ecd-c239-472f-95e4-2b89b4f75800", "name": "Falcon 9 Block 5 | Crew-8", "net": "2024-03-04T03:53:38Z", "pad": {"id": 80}},
+		"launch_crew": [{"id": 1, "role": {"id": 1, "role": "Commander"}, "astronaut": {"id": 680, "name": "Matthew Dominick"}}]}]}`)
+	r, _ := LookupResource("spacecraft-flights")
+
+	page, err := r.decode[ModeDetailed](body)
+	assert.NoError(t, err)
+	doc := page.Docs[0]
+	assert.Equal(t, "e3df2ecd-c239-472f-95e4-2b89b4f75800", doc["launch"].(bson.M)["id"])
+	assert.NotContains(t, doc["launch"].(bson.M), "pad")
+	crew := doc["launch_crew"].(bson.A)
+	assert.EqualValues(t, 680, crew[0].(bson.M)["astronaut"].(bson.M)["id"])
+}
+
+func TestDecodeMockFixtures(t *testing.T) {
+	fixtures, err := ll2mock.LoadFixtures(filepath.Join("..", "..", "cmd", "ll2mock", "fixtures"))
+	assert.NoError(t, err)
+	server := httptest.NewServer(ll2mock.New("2.3.0", fixtures, ll2mock.Faults{}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL}, nil)
+	for _, r := range Resources() {
+		page, err := s.loadPage(context.Background(), r, 10, 0, nil)
+		if assert.NoError(t, err, r.Name) {
+			assert.NotEmpty(t, page.Docs, r.Name)
+			assert.Equal(t, len(fixtures[r.Endpoint]), page.Count, r.Name)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how transient LL2 request failures are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retrying
+	MaxRetries int
+	// BaseDelay is the delay before the first retry, doubled on every following one
+	BaseDelay time.Duration
+	// MaxDelay caps the exponential delay, a Retry-After sent by LL2 is honored regardless
+	MaxDelay time.Duration
+}
+
+// backoff returns the delay before the given retry (starting at 1),
+// an exponential delay with jitter in [d/2, d)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half)
+}
+
+// APIError is returned when LL2 responds with a status code other than 200
+type APIError struct {
+	StatusCode int
+	URL        string
+	// RetryAfter is the delay LL2 asked for on 429 and 503 responses, 0 if none was given
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("LL2 API returned status code %d, url:%s", e.StatusCode, e.URL)
+}
+
+// Temporary reports whether the request may succeed if it is retried
+func (e *APIError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// DecodeError is returned when an LL2 response can not be decoded, retrying it does not help
+type DecodeError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode LL2 %s response: %s", e.Endpoint, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// transportError wraps errors of the HTTP round trip, such as timeouts or reset connections
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// IsTemporary reports whether err is a transient LL2 failure worth retrying,
+// e.g. a 429, a 5xx or a network error. 404s and decode errors are permanent.
+func IsTemporary(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Temporary()
+	}
+	var tErr *transportError
+	return errors.As(err, &tErr)
+}
+
+// retryDelay returns how long to wait before retrying after err
+func (p RetryPolicy) retryDelay(retry int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	return p.backoff(retry)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/ll2mock"
+)
+
+// newRetryTestService returns a service whose retries only wait for milliseconds
+func newRetryTestService(url string, maxRetries int) *LL2Service {
+	return NewLL2Service(&config.Config{
+		LL2URLPrefix:      url,
+		LL2MaxRetries:     maxRetries,
+		LL2RetryBaseDelay: time.Millisecond,
+		LL2RetryMaxDelay:  10 * time.Millisecond,
+	}, nil)
+}
+
+func TestLoadRetriesTransientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if attempts.Add(1) <= 2 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	s := newRetryTestService(server.URL, 3)
+	r, _ := LookupResource("agencies")
+
+	page, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.Len(t, page.Docs, 1)
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestLoadGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s := newRetryTestService(server.URL, 2)
+	r, _ := LookupResource("agencies")
+
+	_, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.True(t, IsTemporary(err))
+	assert.Equal(t, int32(3), attempts.Load())
+}
+
+func TestLoadDoesNotRetryPermanentErrors(t *testing.T) {
+	var attempts atomic.Int32
+	notFound := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+
+	s := newRetryTestService(notFound.URL, 3)
+	r, _ := LookupResource("agencies")
+
+	_, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.Error(t, err)
+	assert.False(t, IsTemporary(err))
+	assert.Equal(t, int32(1), attempts.Load())
+
+	attempts.Store(0)
+	garbage := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		rw.Write([]byte("<html>maintenance</html>"))
+	}))
+	defer garbage.Close()
+
+	s = newRetryTestService(garbage.URL, 3)
+	_, err = s.loadPage(context.Background(), r, 1, 0, nil)
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.False(t, IsTemporary(err))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestLoadHonorsRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	var first, second time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if attempts.Add(1) == 1 {
+			first = time.Now()
+			rw.Header().Set("Retry-After", "1")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		second = time.Now()
+		sampleData, err := os.ReadFile(filepath.Join("testdata", "agencies.json"))
+		if err != nil {
+			t.Fatalf("Failed to read agencies.json: %v", err)
+		}
+		rw.Write(sampleData)
+	}))
+	defer server.Close()
+
+	s := newRetryTestService(server.URL, 1)
+	r, _ := LookupResource("agencies")
+
+	_, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), attempts.Load())
+	// the backoff alone would only wait milliseconds
+	assert.GreaterOrEqual(t, second.Sub(first), time.Second)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry, ceiling := range map[int]time.Duration{1: 100, 2: 200, 3: 400, 4: 800, 5: 1000, 10: 1000} {
+		ceiling *= time.Millisecond
+		for range 20 {
+			d := p.backoff(retry)
+			assert.GreaterOrEqual(t, d, ceiling/2, "retry %d", retry)
+			assert.Less(t, d, ceiling, "retry %d", retry)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	d := parseRetryAfter(date)
+	assert.Greater(t, d, 50*time.Second)
+	assert.LessOrEqual(t, d, time.Minute)
+}
+
+func TestLoadStopsWhenContextIsDone(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts.Add(1)
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	s := newRetryTestService(server.URL, 3)
+	r, _ := LookupResource("agencies")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.loadPage(ctx, r, 1, 0, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, IsTemporary(err))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestLoadPagesFromMockWithFaults(t *testing.T) {
+	fixtures := map[string][]map[string]any{
+		"agencies": {{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}},
+	}
+	server := httptest.NewServer(ll2mock.New("2.3.0", fixtures, ll2mock.Faults{ThrottleEvery: 2, ErrorEvery: 5}))
+	defer server.Close()
+
+	s := newRetryTestService(server.URL, 3)
+	r, _ := LookupResource("agencies")
+
+	ids := []any{}
+	for offset := 0; offset < 3; offset += 2 {
+		page, err := s.loadPage(context.Background(), r, 2, offset, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, page.Count)
+		for _, doc := range page.Docs {
+			ids = append(ids, doc["id"])
+		}
+	}
+	assert.EqualValues(t, []any{int32(1), int32(2), int32(3)}, ids)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Scheduler periodically starts LL2 syncs, each resource on its own cron schedule
+type Scheduler struct {
+	cron *cron.Cron
+}
+
+// NewScheduler creates a scheduler from a map of resource name to cron expression.
+// Both standard 5-field expressions and descriptors like "@daily" or "@every 15m" are accepted.
+func NewScheduler(s *LL2Service, schedules map[string]string) (*Scheduler, error) {
+	c := cron.New()
+	for resource, spec := range schedules {
+		if _, ok := LookupResource(resource); !ok {
+			return nil, fmt.Errorf("unknown LL2 resource %q in schedule", resource)
+		}
+		_, err := c.AddFunc(spec, func() {
+			job, err := s.Update(context.Background(), resource, true, SyncOptions{})
+			if errors.Is(err, ErrJobRunning) {
+				logrus.Infof("Skipping scheduled LL2 %s sync: %s", resource, err)
+				return
+			}
+			if err != nil {
+				logrus.Errorf("Failed to start scheduled LL2 %s sync: %s", resource, err)
+				return
+			}
+			logrus.Infof("Started scheduled LL2 %s sync, job %s", resource, job.ID)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q for LL2 resource %s: %w", spec, resource, err)
+		}
+		logrus.Infof("Scheduled LL2 %s sync at %q", resource, spec)
+	}
+	return &Scheduler{cron: c}, nil
+}
+
+// Start runs the scheduler in background
+func (sc *Scheduler) Start() {
+	sc.cron.Start()
+}
+
+// Stop stops scheduling new syncs and waits for the scheduler to finish or ctx to expire
+func (sc *Scheduler) Stop(ctx context.Context) error {
+	select {
+	case <-sc.cron.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
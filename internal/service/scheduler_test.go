@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+func TestNewScheduler(t *testing.T) {
+	s := NewLL2Service(&config.Config{}, nil)
+
+	sc, err := NewScheduler(s, map[string]string{
+		"launches": "*/15 * * * *",
+		"agencies": "@daily",
+		"pads":     "@weekly",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sc.cron.Entries(), 3)
+
+	sc.Start()
+	assert.NoError(t, sc.Stop(context.Background()))
+
+	_, err = NewScheduler(s, map[string]string{"rockets": "@daily"})
+	assert.ErrorContains(t, err, "unknown LL2 resource")
+
+	_, err = NewScheduler(s, map[string]string{"launches": "every now and then"})
+	assert.ErrorContains(t, err, "invalid schedule")
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+// LL2 response modes, each decoded into the matching LL2*Mini/List, LL2*Normal or LL2*Detailed model
+const (
+	ModeList     = "list"
+	ModeNormal   = "normal"
+	ModeDetailed = "detailed"
+)
+
+const (
+	defaultLL2APIVersion = "2.3.0"
+	defaultLL2PageSize   = 10
+	// maxLL2PageSize is the largest limit LL2 accepts
+	maxLL2PageSize = 100
+)
+
+// RequestSettings controls how a resource is requested from LL2
+type RequestSettings struct {
+	Version  string
+	Mode     string
+	PageSize int
+}
+
+// requestConfig holds the default request settings and their overrides by resource name
+type requestConfig struct {
+	defaults  RequestSettings
+	versions  map[string]string
+	modes     map[string]string
+	pageSizes map[string]int
+}
+
+func newRequestConfig(conf *config.Config) requestConfig {
+	rc := requestConfig{
+		defaults: RequestSettings{
+			Version:  conf.LL2APIVersion,
+			Mode:     conf.LL2Mode,
+			PageSize: conf.LL2PageSize,
+		},
+		versions:  conf.LL2APIVersions,
+		modes:     conf.LL2Modes,
+		pageSizes: conf.LL2PageSizes,
+	}
+	if rc.defaults.Version == "" {
+		rc.defaults.Version = defaultLL2APIVersion
+	}
+	if rc.defaults.Mode == "" {
+		rc.defaults.Mode = ModeDetailed
+	}
+	if rc.defaults.PageSize <= 0 {
+		rc.defaults.PageSize = defaultLL2PageSize
+	}
+	return rc
+}
+
+// settings returns the request settings of r, its overrides applied to the defaults.
+// Resources that do not support the default mode are requested in detailed mode.
+func (rc requestConfig) settings(r *Resource) RequestSettings {
+	rs := rc.defaults
+	if _, ok := r.decode[rs.Mode]; !ok {
+		rs.Mode = ModeDetailed
+	}
+	if v, ok := rc.versions[r.Name]; ok && v != "" {
+		rs.Version = v
+	}
+	if m, ok := rc.modes[r.Name]; ok && m != "" {
+		rs.Mode = m
+	}
+	if n, ok := rc.pageSizes[r.Name]; ok && n > 0 {
+		rs.PageSize = n
+	}
+	return rs
+}
+
+// validate checks that the default mode is known, overrides name declared resources
+// and every resource can decode its mode
+func (rc requestConfig) validate() error {
+	switch rc.defaults.Mode {
+	case ModeList, ModeNormal, ModeDetailed:
+	default:
+		return fmt.Errorf("unknown LL2 mode %q, supported: %v", rc.defaults.Mode, []string{ModeList, ModeNormal, ModeDetailed})
+	}
+	for _, overrides := range []map[string]string{rc.versions, rc.modes} {
+		for name := range overrides {
+			if _, ok := LookupResource(name); !ok {
+				return fmt.Errorf("unknown LL2 resource %q in request settings", name)
+			}
+		}
+	}
+	for name := range rc.pageSizes {
+		if _, ok := LookupResource(name); !ok {
+			return fmt.Errorf("unknown LL2 resource %q in request settings", name)
+		}
+	}
+	for _, r := range resources {
+		rs := rc.settings(r)
+		if _, ok := r.decode[rs.Mode]; !ok {
+			return fmt.Errorf("LL2 resource %s does not support mode %q, supported: %v", r.Name, rs.Mode, r.Modes())
+		}
+		if rs.PageSize > maxLL2PageSize {
+			return fmt.Errorf("page size %d of LL2 resource %s exceeds %d", rs.PageSize, r.Name, maxLL2PageSize)
+		}
+	}
+	return nil
+}
+
+// Modes returns the LL2 modes r can be requested in
+func (r *Resource) Modes() []string {
+	modes := make([]string, 0, len(r.decode))
+	for mode := range r.decode {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
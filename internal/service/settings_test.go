@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+func TestRequestSettings(t *testing.T) {
+	rc := newRequestConfig(&config.Config{
+		LL2Modes:     map[string]string{"launches": ModeNormal},
+		LL2PageSizes: map[string]int{"launches": 100},
+	})
+	launches, _ := LookupResource("launches")
+	pads, _ := LookupResource("pads")
+
+	assert.Equal(t, RequestSettings{Version: "2.3.0", Mode: ModeNormal, PageSize: 100}, rc.settings(launches))
+	assert.Equal(t, RequestSettings{Version: "2.3.0", Mode: ModeDetailed, PageSize: 10}, rc.settings(pads))
+	assert.NoError(t, rc.validate())
+}
+
+func TestRequestSettingsFallBackToDetailed(t *testing.T) {
+	rc := newRequestConfig(&config.Config{LL2Mode: ModeNormal})
+	launches, _ := LookupResource("launches")
+	pads, _ := LookupResource("pads")
+
+	assert.Equal(t, ModeNormal, rc.settings(launches).Mode)
+	// pads are only served in detailed mode
+	assert.Equal(t, ModeDetailed, rc.settings(pads).Mode)
+	assert.NoError(t, rc.validate())
+
+	assert.NoError(t, newRequestConfig(&config.Config{LL2Mode: ModeList}).validate())
+}
+
+func TestRequestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config.Config
+	}{
+		{"unknown resource", config.Config{LL2APIVersions: map[string]string{"rockets": "2.2.0"}}},
+		{"unsupported mode", config.Config{LL2Modes: map[string]string{"pads": ModeList}}},
+		{"unsupported default mode", config.Config{LL2Mode: "compact"}},
+		{"page size too large", config.Config{LL2PageSizes: map[string]int{"agencies": 500}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, newRequestConfig(&tt.conf).validate())
+		})
+	}
+}
+
+func TestLoadPageUsesRequestSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/2.4.0/agencies", req.URL.Path)
+		assert.Equal(t, ModeList, req.URL.Query().Get("mode"))
+		rw.Write([]byte(`{"count": 1, "results": [{"id": 225, "name": "1worldspace", "abbrev": "1WS", "total_launch_count": 0}]}`))
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{
+		LL2URLPrefix:   server.URL,
+		LL2APIVersions: map[string]string{"agencies": "2.4.0"},
+		LL2Modes:       map[string]string{"agencies": ModeList},
+	}, nil)
+	r, _ := LookupResource("agencies")
+
+	page, err := s.loadPage(context.Background(), r, 1, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1WS", page.Docs[0]["abbrev"])
+	// list mode decodes into the mini model, detailed fields are not written
+	assert.NotContains(t, page.Docs[0], "total_launch_count")
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vamosdalian/launchdate-backend/internal/models"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DockedStation lists the vehicles currently docked at a space station
+type DockedStation struct {
+	Station  models.LL2SpaceStationMini       `json:"station"`
+	Vehicles []models.LL2DockingEventDetailed `json:"vehicles"`
+}
+
+// DockedVehicles returns the vehicles docked at each station, derived from docking events
+// that have docked but not departed yet. Stations without docked vehicles are left out.
+func (s *LL2Service) DockedVehicles(ctx context.Context) ([]DockedStation, error) {
+	r, _ := LookupResource("docking-events")
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := map[string]any{
+		removedAtField: map[string]any{"$exists": false},
+		"docking": map[string]any{
+			"$ne":  "",
+			"$lte": time.Now().UTC().Format(time.RFC3339),
+		},
+		"departure": map[string]any{"$in": []any{"", nil}},
+	}
+	opts := options.Find().SetSort(map[string]int{"docking": 1})
+	cursor, err := s.mongoClient.Collection(r.Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.LL2DockingEventDetailed
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	stations := []DockedStation{}
+	index := map[int]int{}
+	for _, event := range events {
+		i, ok := index[event.SpaceStationTarget.ID]
+		if !ok {
+			i = len(stations)
+			index[event.SpaceStationTarget.ID] = i
+			stations = append(stations, DockedStation{Station: event.SpaceStationTarget})
+		}
+		stations[i].Vehicles = append(stations[i].Vehicles, event)
+	}
+	return stations, nil
+}
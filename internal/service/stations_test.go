@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+)
+
+func TestDockedVehicles(t *testing.T) {
+	mongoDB, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	iss := map[string]any{"id": 4, "name": "International Space Station"}
+	tiangong := map[string]any{"id": 18, "name": "Tiangong Space Station"}
+	_, err := mongoDB.Collection("ll2_docking_event").InsertMany(context.Background(), []any{
+		map[string]any{"id": 1, "docking": "2024-03-05T11:28:00Z", "departure": "", "space_station_target": iss},
+		map[string]any{"id": 2, "docking": "2023-08-27T13:16:00Z", "departure": "2024-03-11T15:20:00Z", "space_station_target": iss},
+		map[string]any{"id": 3, "docking": "2024-04-26T03:32:00Z", "departure": "", "space_station_target": tiangong},
+		map[string]any{"id": 4, "docking": "2099-01-01T00:00:00Z", "departure": "", "space_station_target": iss},
+		map[string]any{"id": 5, "docking": "2024-01-01T00:00:00Z", "departure": "", "space_station_target": iss, "removed_at": "2024-02-01T00:00:00Z"},
+	})
+	assert.NoError(t, err)
+
+	s := NewLL2Service(&config.Config{}, mongoDB)
+	stations, err := s.DockedVehicles(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, stations, 2) {
+		assert.Equal(t, 4, stations[0].Station.ID)
+		assert.Len(t, stations[0].Vehicles, 1)
+		assert.Equal(t, 1, stations[0].Vehicles[0].ID)
+		assert.Equal(t, 18, stations[1].Station.ID)
+	}
+}
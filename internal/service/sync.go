@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vamosdalian/launchdate-backend/internal/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SyncOptions controls a single sync run
+type SyncOptions struct {
+	// Full crawls every record from offset 0, even if the resource supports incremental syncs
+	Full bool
+}
+
+// Update starts a sync of the named resource.
+// if async is true, function runs in background
+// otherwise, it runs synchronously
+func (s *LL2Service) Update(ctx context.Context, name string, async bool, opts SyncOptions) (Job, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return Job{}, fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	return s.jobs.Start(ctx, r.Name, async, func(ctx context.Context, t *JobTracker) error {
+		return s.sync(ctx, t, r, opts)
+	})
+}
+
+// sync pages through the LL2 endpoint of r and upserts every page into its collection.
+// It continues from the checkpoint of r if a previous run did not complete,
+// and stops at an empty page, once count records have been fetched or when ctx is done.
+// Records it did not see are only marked as removed if it fetched all count records
+// and wrote as many distinct ones.
+func (s *LL2Service) sync(ctx context.Context, t *JobTracker, r *Resource, opts SyncOptions) error {
+	cp, err := s.loadCheckpoint(ctx, r.Name)
+	if err != nil {
+		return err
+	}
+	// a full resync does not continue an interrupted incremental one
+	if opts.Full && cp.Cursor != "" {
+		cp = &SyncCheckpoint{Resource: r.Name}
+	}
+	if cp.Pages == 0 {
+		cp.RunID = uuid.NewString()
+	}
+	// a resumed sync keeps the filter it started with
+	query, err := url.ParseQuery(cp.Cursor)
+	if err != nil {
+		return err
+	}
+	if !opts.Full && r.IncrementalField != "" && cp.Pages == 0 {
+		since, err := s.latestValue(ctx, r, r.IncrementalField)
+		if err != nil {
+			return err
+		}
+		if since != "" {
+			query.Set(r.IncrementalField+"__gte", since)
+			query.Set("ordering", r.IncrementalField)
+		}
+		cp.Cursor = query.Encode()
+	}
+
+	rl := util.NewAdaptiveRateLimit(time.Duration(s.LL2RequestInterval) * time.Second)
+	defer rl.Close()
+	s.adaptRate(ctx, rl)
+	if cp.Cursor != "" {
+		logrus.Infof("Starting LL2 %s update with filter %s from offset %d...", r.Name, cp.Cursor, cp.Offset)
+	} else {
+		logrus.Infof("Starting LL2 %s update from offset %d...", r.Name, cp.Offset)
+	}
+
+	pageSize := s.requests.settings(r).PageSize
+	offset := cp.Offset
+	pages := 0
+	// complete is set once a page reaches the count LL2 reported, a run ended by an empty page is not complete
+	complete := false
+	count := 0
+	for {
+		if err = rl.WaitContext(ctx); err != nil {
+			return err
+		}
+		page, err := s.loadPage(ctx, r, pageSize, offset, query)
+		if err != nil {
+			return err
+		}
+		if len(page.Docs) == 0 {
+			break
+		}
+		logrus.Infof("Fetched %d/%d %s from LL2", offset+len(page.Docs), page.Count, r.Name)
+
+		if r.prepare != nil {
+			if err := r.prepare(ctx, s, page.Docs); err != nil {
+				return err
+			}
+		}
+
+		// stored versions are diffed before they are overwritten
+		stored, err := s.findStored(ctx, r, page.Docs)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		slips, err := s.recordHistory(ctx, r, page.Docs, stored, now)
+		if err != nil {
+			return err
+		}
+		changes, err := s.recordChanges(ctx, r, page.Docs, stored, now)
+		if err != nil {
+			return err
+		}
+		if slips > 0 || changes > 0 {
+			logrus.Infof("Recorded %d history entries and %d field changes of %s", slips, changes, r.Name)
+		}
+
+		writes := make([]mongo.WriteModel, 0, len(page.Docs))
+		for _, doc := range page.Docs {
+			doc[syncRunField] = cp.RunID
+			writes = append(writes, upsertModel(r.IDField, doc[r.IDField], doc))
+		}
+		res, err := s.bulkUpsert(ctx, r.Collection, writes)
+		if err != nil {
+			return err
+		}
+		logrus.Infof("Wrote %d %s: %d matched, %d modified, %d upserted", len(writes), r.Name, res.Matched, res.Modified, res.Upserted)
+		t.AddPage(res)
+
+		count = page.Count
+		done := offset+len(page.Docs) >= page.Count
+		offset = advanceCursor(r, query, page.Docs, offset)
+		cp.Cursor = query.Encode()
+		if err = s.commitPage(ctx, cp, offset); err != nil {
+			return err
+		}
+		if done {
+			complete = true
+			break
+		}
+		if pages++; pages%throttleCheckPages == 0 {
+			s.adaptRate(ctx, rl)
+		}
+	}
+
+	// only a complete sync without filter has seen every record, anything it did not see was removed upstream
+	switch {
+	case cp.Cursor != "" || cp.RunID == "":
+		// a filtered run, or one resumed from a checkpoint written before runs were tracked, can not tell
+	case !complete:
+		logrus.Warnf("LL2 %s update ended at offset %d before reaching the reported count, not marking records as removed", r.Name, offset)
+	default:
+		// records moving between pages while the run pages by offset are never seen by it
+		seen, err := s.countRun(ctx, r, cp.RunID)
+		if err != nil {
+			return err
+		}
+		if seen < int64(count) {
+			logrus.Warnf("LL2 %s update saw %d of %d records, not marking records as removed", r.Name, seen, count)
+			break
+		}
+		removed, err := s.tombstone(ctx, r, cp.RunID)
+		if err != nil {
+			return err
+		}
+		if removed > 0 {
+			logrus.Infof("Marked %d %s as removed upstream", removed, r.Name)
+		}
+		t.AddRemoved(removed)
+	}
+	return s.ResetCheckpoint(ctx, r.Name)
+}
+
+// advanceCursor returns the offset of the page after docs.
+// An incremental sync moves its lower bound in query to the last value it has seen instead,
+// so records updated meanwhile move behind the bound rather than shifting unseen records before the offset.
+// The last record, and any sharing its value, is fetched again, unless the whole page shares the bound.
+func advanceCursor(r *Resource, query url.Values, docs []bson.M, offset int) int {
+	key := r.IncrementalField + "__gte"
+	since := query.Get(key)
+	if since == "" {
+		return offset + len(docs)
+	}
+	last, _ := docs[len(docs)-1][r.IncrementalField].(string)
+	if last == "" || last == since {
+		return offset + len(docs)
+	}
+	query.Set(key, last)
+	return 0
+}
+
+// loadPage fetches and decodes a single page of r in the version and mode configured for r
+func (s *LL2Service) loadPage(ctx context.Context, r *Resource, limit, offset int, query url.Values) (*resourcePage, error) {
+	rs := s.requests.settings(r)
+	decode, ok := r.decode[rs.Mode]
+	if !ok {
+		return nil, fmt.Errorf("LL2 resource %s does not support mode %q", r.Name, rs.Mode)
+	}
+	body, err := s.fetchFromAPI(ctx, rs.Version, r.Endpoint, rs.Mode, limit, offset, query)
+	if err != nil {
+		return nil, err
+	}
+	page, err := decode(body)
+	if err != nil {
+		return nil, &DecodeError{Endpoint: r.Endpoint, Err: err}
+	}
+	return page, nil
+}
+
+// latestValue returns the greatest stored value of field in the collection of r,
+// or an empty string if the collection is empty
+func (s *LL2Service) latestValue(ctx context.Context, r *Resource, field string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	opts := options.FindOne().
+		SetSort(map[string]int{field: -1}).
+		SetProjection(map[string]int{field: 1})
+	var doc bson.M
+	err := s.mongoClient.Collection(r.Collection).FindOne(ctx, map[string]any{}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	value, _ := doc[field].(string)
+	return value, nil
+}
+
+// ErrNotFound is returned when no stored document matches
+var ErrNotFound = errors.New("not found")
+
+// Get returns the stored document of the named resource with the given id, or slug if it has one
+func (s *LL2Service) Get(ctx context.Context, name, id string) (any, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := map[string]any{r.IDField: parseID(id)}
+	if r.SlugField != "" {
+		filter = map[string]any{"$or": []map[string]any{filter, {r.SlugField: id}}}
+	}
+	res := s.mongoClient.Collection(r.Collection).FindOne(ctx, filter)
+	if errors.Is(res.Err(), mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if res.Err() != nil {
+		return nil, res.Err()
+	}
+	return r.detail(res)
+}
+
+// ListOptions selects the page and documents a list from DB returns
+type ListOptions struct {
+	Limit  int
+	Offset int
+	// IncludeRemoved also returns documents removed upstream
+	IncludeRemoved bool
+	// Filters holds values of the resource's declared Filters by query parameter
+	Filters map[string]string
+}
+
+// List returns a page of the named resource from DB
+func (s *LL2Service) List(ctx context.Context, name string, opts ListOptions) (any, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	filter, err := buildFilter(r, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, r, filter, bson.D{{Key: r.SortField, Value: 1}}, opts)
+}
+
+// ListLaunches returns a page of the launches linked to the record of the named resource with the given id
+func (s *LL2Service) ListLaunches(ctx context.Context, name, id string, opts ListOptions) (any, error) {
+	r, ok := LookupResource(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown LL2 resource %q", name)
+	}
+	if r.LaunchesField == "" {
+		return nil, fmt.Errorf("LL2 resource %s has no launches", name)
+	}
+	launches, _ := LookupResource("launches")
+	filter, err := buildFilter(launches, opts.Filters)
+	if err != nil {
+		return nil, err
+	}
+	filter[r.LaunchesField] = parseID(id)
+	return s.list(ctx, launches, filter, bson.D{{Key: launches.SortField, Value: 1}}, opts)
+}
+
+// list returns a page of the documents of r matching filter in the given order
+func (s *LL2Service) list(ctx context.Context, r *Resource, filter map[string]any, sort bson.D, opts ListOptions) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(opts.Limit))
+	findOptions.SetSkip(int64(opts.Offset))
+	findOptions.SetSort(sort)
+
+	if !opts.IncludeRemoved {
+		filter[removedAtField] = map[string]any{"$exists": false}
+	}
+	cursor, err := s.mongoClient.Collection(r.Collection).Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	return r.list(ctx, cursor)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vamosdalian/launchdate-backend/internal/models"
+	"github.com/vamosdalian/launchdate-backend/internal/util"
+)
+
+// throttleCheckPages is how many pages are fetched between two reads of the LL2 request budget
+const throttleCheckPages = 10
+
+// LoadThrottle returns the remaining LL2 request budget, reading it does not count against the budget
+func (s *LL2Service) LoadThrottle(ctx context.Context) (*models.LL2Throttle, error) {
+	body, err := s.fetchURL(ctx, fmt.Sprintf("%s/%s/api-throttle/", s.LL2URLPrefix, s.requests.defaults.Version))
+	if err != nil {
+		return nil, err
+	}
+	var throttle models.LL2Throttle
+	if err := json.Unmarshal(body, &throttle); err != nil {
+		return nil, &DecodeError{Endpoint: "api-throttle", Err: err}
+	}
+	return &throttle, nil
+}
+
+// adaptRate reads the LL2 request budget and adjusts rl to match it,
+// the configured interval is kept if adaptive rate limiting is off or the budget can not be read.
+// Without a configured interval, the budget of the API token is followed regardless.
+func (s *LL2Service) adaptRate(ctx context.Context, rl *util.AdaptiveRateLimit) {
+	if !s.adaptiveRate && !s.tierInterval {
+		return
+	}
+	throttle, err := s.LoadThrottle(ctx)
+	if err != nil {
+		logrus.Warnf("Failed to read LL2 api throttle, keeping request interval %s: %s", rl.Interval(), err)
+		return
+	}
+	applyThrottle(rl, throttle)
+}
+
+// applyThrottle spreads the requests of a budget evenly over its window,
+// and pauses rl until the next request is allowed once the budget is used up
+func applyThrottle(rl *util.AdaptiveRateLimit, throttle *models.LL2Throttle) {
+	if throttle.YourRequestLimit <= 0 || throttle.LimitFrequencySecs <= 0 {
+		return
+	}
+	interval := time.Duration(throttle.LimitFrequencySecs) * time.Second / time.Duration(throttle.YourRequestLimit)
+	if interval != rl.Interval() {
+		logrus.Infof("LL2 allows %d requests per %ds, request interval is now %s",
+			throttle.YourRequestLimit, throttle.LimitFrequencySecs, interval)
+		rl.SetInterval(interval)
+	}
+	if throttle.CurrentUse >= throttle.YourRequestLimit {
+		pause := time.Duration(throttle.NextUseSecs) * time.Second
+		logrus.Infof("LL2 request budget used up, pausing for %s", pause)
+		rl.PauseUntil(time.Now().Add(pause))
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamosdalian/launchdate-backend/internal/config"
+	"github.com/vamosdalian/launchdate-backend/internal/models"
+	"github.com/vamosdalian/launchdate-backend/internal/util"
+)
+
+func TestLoadThrottle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/2.3.0/api-throttle/", req.URL.Path)
+		rw.Write([]byte(`{"your_request_limit": 15, "limit_frequency_secs": 3600, "current_use": 15, "next_use_secs": 120, "ident": "127.0.0.1"}`))
+	}))
+	defer server.Close()
+
+	s := NewLL2Service(&config.Config{LL2URLPrefix: server.URL}, nil)
+
+	throttle, err := s.LoadThrottle(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 15, throttle.YourRequestLimit)
+	assert.Equal(t, 3600, throttle.LimitFrequencySecs)
+	assert.Equal(t, 15, throttle.CurrentUse)
+	assert.Equal(t, 120, throttle.NextUseSecs)
+}
+
+func TestApplyThrottle(t *testing.T) {
+	rl := util.NewAdaptiveRateLimit(5 * time.Second)
+	defer rl.Close()
+
+	// plenty of budget left, spread it over the window
+	applyThrottle(rl, &models.LL2Throttle{YourRequestLimit: 3600, LimitFrequencySecs: 3600, CurrentUse: 10})
+	assert.Equal(t, time.Second, rl.Interval())
+	assert.True(t, rl.Allow())
+
+	// a smaller budget slows down
+	applyThrottle(rl, &models.LL2Throttle{YourRequestLimit: 15, LimitFrequencySecs: 3600, CurrentUse: 3})
+	assert.Equal(t, 4*time.Minute, rl.Interval())
+
+	// a used up budget pauses until the next request is allowed
+	rl.SetInterval(0)
+	applyThrottle(rl, &models.LL2Throttle{YourRequestLimit: 3600, LimitFrequencySecs: 3600, CurrentUse: 3600, NextUseSecs: 60})
+	assert.False(t, rl.Allow())
+
+	// an unknown budget keeps the interval
+	applyThrottle(rl, &models.LL2Throttle{})
+	assert.Equal(t, time.Second, rl.Interval())
+}
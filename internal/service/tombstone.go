@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// removedAtField is set on documents that were not seen by a full sync, i.e. removed upstream
+	removedAtField = "removed_at"
+	// syncRunField is set on every written document to the run of the sync that last saw it
+	syncRunField = "_sync_run"
+)
+
+// countRun returns how many documents of r were written by the given run
+func (s *LL2Service) countRun(ctx context.Context, r *Resource, runID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return s.mongoClient.Collection(r.Collection).CountDocuments(ctx, map[string]any{syncRunField: runID})
+}
+
+// tombstone marks documents of r not written by the given run as removed upstream,
+// it must only be called after a full sync has seen as many records as LL2 counts
+func (s *LL2Service) tombstone(ctx context.Context, r *Resource, runID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	filter := map[string]any{
+		syncRunField:   map[string]any{"$ne": runID},
+		removedAtField: map[string]any{"$exists": false},
+	}
+	update := map[string]any{
+		"$set": map[string]any{removedAtField: time.Now()},
+	}
+	res, err := s.mongoClient.Collection(r.Collection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RateLimiter interface {
+	Allow() bool
+	Wait()
+	Close()
+}
+
+// AdaptiveRateLimit is a RateLimiter whose interval can be changed while it is in use,
+// and which can be paused until a given time, e.g. when an API budget is used up
+type AdaptiveRateLimit struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time // time the last token was handed out for
+	pause    time.Time // no token is handed out before this time
+	closed   bool
+}
+
+func NewAdaptiveRateLimit(interval time.Duration) *AdaptiveRateLimit {
+	return &AdaptiveRateLimit{
+		interval: interval,
+	}
+}
+
+// next returns the earliest time the next token can be handed out
+func (r *AdaptiveRateLimit) next() time.Time {
+	next := r.last.Add(r.interval)
+	if r.pause.After(next) {
+		return r.pause
+	}
+	return next
+}
+
+func (r *AdaptiveRateLimit) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
+	}
+	now := time.Now()
+	if now.Before(r.next()) {
+		return false
+	}
+	r.last = now
+	return true
+}
+
+// Wait blocks until a token is available, it returns immediately once the limiter is closed
+func (r *AdaptiveRateLimit) Wait() {
+	_ = r.WaitContext(context.Background())
+}
+
+// WaitContext is like Wait but gives up and returns the error of ctx once it is done
+func (r *AdaptiveRateLimit) WaitContext(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	at := r.next()
+	if at.Before(now) {
+		at = now
+	}
+	r.last = at
+	r.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *AdaptiveRateLimit) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+}
+
+// Interval returns the current interval between two tokens
+func (r *AdaptiveRateLimit) Interval() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.interval
+}
+
+// SetInterval changes the interval between two tokens, it also applies to the next token
+func (r *AdaptiveRateLimit) SetInterval(interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interval = interval
+}
+
+// PauseUntil hands out no token before t
+func (r *AdaptiveRateLimit) PauseUntil(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t.After(r.pause) {
+		r.pause = t
+	}
+}
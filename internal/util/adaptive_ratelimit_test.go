@@ -0,0 +1,104 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveRateLimit_SetInterval(t *testing.T) {
+	limiter := NewAdaptiveRateLimit(time.Hour)
+	defer limiter.Close()
+
+	// The first token is available immediately
+	if !limiter.Allow() {
+		t.Fatal("Expected first call to be allowed, but it was not")
+	}
+	if limiter.Allow() {
+		t.Error("Expected second call to be denied, but it was allowed")
+	}
+
+	// Speeding up also applies to the token we are waiting for
+	rate := 50 * time.Millisecond
+	limiter.SetInterval(rate)
+	startTime := time.Now()
+	limiter.Wait()
+	elapsed := time.Since(startTime)
+	if elapsed > rate*2 {
+		t.Errorf("Expected Wait() to block for around %v after speeding up, but it blocked for %v", rate, elapsed)
+	}
+	if limiter.Interval() != rate {
+		t.Errorf("Expected interval %v, got %v", rate, limiter.Interval())
+	}
+}
+
+func TestAdaptiveRateLimit_PauseUntil(t *testing.T) {
+	rate := 10 * time.Millisecond
+	limiter := NewAdaptiveRateLimit(rate)
+	defer limiter.Close()
+
+	limiter.Wait()
+
+	pause := 150 * time.Millisecond
+	limiter.PauseUntil(time.Now().Add(pause))
+	if limiter.Allow() {
+		t.Error("Expected call during pause to be denied, but it was allowed")
+	}
+
+	startTime := time.Now()
+	limiter.Wait()
+	elapsed := time.Since(startTime)
+	if elapsed < pause-rate {
+		t.Errorf("Expected Wait() to block until the pause ends, but it blocked for %v", elapsed)
+	}
+
+	// After the pause the normal interval applies again
+	startTime = time.Now()
+	limiter.Wait()
+	elapsed = time.Since(startTime)
+	if elapsed > rate*5 {
+		t.Errorf("Expected Wait() to block for around %v after the pause, but it blocked for %v", rate, elapsed)
+	}
+}
+
+func TestAdaptiveRateLimit_Close(t *testing.T) {
+	limiter := NewAdaptiveRateLimit(time.Hour)
+	limiter.Wait()
+	limiter.Close()
+
+	if limiter.Allow() {
+		t.Error("Expected Allow() to return false after Close(), but it returned true")
+	}
+
+	// Wait must not block forever on a closed limiter
+	done := make(chan struct{})
+	go func() {
+		limiter.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected Wait() to return after Close()")
+	}
+}
+
+func TestAdaptiveRateLimit_WaitContext(t *testing.T) {
+	limiter := NewAdaptiveRateLimit(time.Hour)
+	defer limiter.Close()
+
+	if err := limiter.WaitContext(context.Background()); err != nil {
+		t.Fatalf("Expected first token without error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	err := limiter.WaitContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(startTime); elapsed > time.Second {
+		t.Errorf("Expected WaitContext() to return when ctx is done, but it blocked for %v", elapsed)
+	}
+}